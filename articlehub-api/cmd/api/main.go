@@ -45,6 +45,11 @@ func main() {
 
 	server.RegisterFiberRoutes()
 
+	// Background jobs stop once main returns after the graceful shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	server.StartBackgroundJobs(jobsCtx)
+
 	// Create a done channel to signal when the shutdown is complete
 	done := make(chan bool, 1)
 
@@ -0,0 +1,57 @@
+// Package analytics holds the privacy preserving pieces of article view
+// tracking: telling bots apart, identifying visitors without storing who they
+// are, and reducing referrers to something worth aggregating.
+package analytics
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// botPattern matches the user agents of crawlers, link previewers, monitoring
+// services and HTTP libraries. Browsers never contain any of these words.
+var botPattern = regexp.MustCompile(`(?i)bot\b|bot/|crawl|spider|slurp|archiver|facebookexternalhit|` +
+	`embedly|preview|headless|phantomjs|lighthouse|pingdom|uptime|monitor|feedfetcher|` +
+	`curl/|wget/|httpie|python-|go-http-client|java/|okhttp|axios/|node-fetch|libwww|scrapy|postman`)
+
+// IsBot reports whether a request with the given User-Agent comes from an
+// automated client. Requests without a user agent are treated as bots.
+func IsBot(userAgent string) bool {
+	return strings.TrimSpace(userAgent) == "" || botPattern.MatchString(userAgent)
+}
+
+// Day truncates t to the UTC day visits are counted in.
+func Day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// VisitorID identifies a visitor for a single day. The same IP address and
+// user agent yield the same ID only as long as the day's salt is the same,
+// so visitors cannot be followed from one day to the next.
+func VisitorID(salt []byte, ip, userAgent string) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(ip))
+	mac.Write([]byte{0})
+	mac.Write([]byte(userAgent))
+	return mac.Sum(nil)
+}
+
+// Referrer reduces a referring URL to its host name without a leading www.
+// It returns an empty string for direct visits, unparsable URLs and links
+// from the site itself, whose host is siteHost.
+func Referrer(referrer, siteHost string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host == "" || host == strings.TrimPrefix(strings.ToLower(siteHost), "www.") {
+		return ""
+	}
+	return host
+}
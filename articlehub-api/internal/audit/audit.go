@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"time"
+
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Service records security relevant actions in the audit log.
+type Service struct {
+	Repo repository.AuditRepository
+}
+
+func NewService(repo repository.AuditRepository) *Service {
+	return &Service{Repo: repo}
+}
+
+// Record appends entry to the audit log, filling in the request IP and user
+// agent, and the authenticated user as actor when ActorID is empty. During
+// impersonation the administrator is the actor and the impersonated user the
+// subject. Failures are logged rather than returned so that auditing never
+// breaks a request.
+func (s *Service) Record(c *fiber.Ctx, entry model.AuditEntry) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		log.Printf("error generating audit entry ID: %v", err)
+		return
+	}
+
+	entry.ID = id.String()
+	entry.IP = c.IP()
+	entry.UserAgent = c.Get(fiber.HeaderUserAgent)
+	if impersonator := middleware.ImpersonatorID(c); impersonator != "" {
+		entry.SubjectID = middleware.CurrentUserID(c)
+		if entry.ActorID == "" {
+			entry.ActorID = impersonator
+		}
+	} else if entry.ActorID == "" {
+		entry.ActorID = middleware.CurrentUserID(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.Repo.CreateEntry(ctx, &entry); err != nil {
+		log.Printf("error recording audit entry %s: %v", entry.Action, err)
+	}
+}
+
+// RecordImpersonatedRequest records a request made by an administrator
+// impersonating a user. It implements middleware.ImpersonationRecorder.
+func (s *Service) RecordImpersonatedRequest(c *fiber.Ctx) {
+	s.Record(c, model.AuditEntry{
+		Action:     model.AuditActionImpersonatedRequest,
+		TargetType: "request",
+		TargetID:   c.Method() + " " + c.OriginalURL(),
+	})
+}
+
+// Diff returns the fields whose JSON representation differs between before
+// and after. Fields hidden from JSON, such as password hashes, never appear.
+func Diff(before, after any) map[string]model.AuditChange {
+	b, a := toMap(before), toMap(after)
+
+	changes := map[string]model.AuditChange{}
+	for key, from := range b {
+		if to, ok := a[key]; !ok || !reflect.DeepEqual(from, to) {
+			changes[key] = model.AuditChange{From: from, To: a[key]}
+		}
+	}
+	for key, to := range a {
+		if _, ok := b[key]; !ok {
+			changes[key] = model.AuditChange{To: to}
+		}
+	}
+	return changes
+}
+
+func toMap(v any) map[string]any {
+	m := map[string]any{}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	json.Unmarshal(data, &m)
+	return m
+}
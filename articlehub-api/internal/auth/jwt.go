@@ -10,10 +10,32 @@ import (
 
 var secret_key = []byte(os.Getenv("SECRET_KEY"))
 
-func CreateToken(id string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":  id,
-		"exp": time.Now().Add(time.Hour * 24).Unix(),
+// Claims are the JWT claims issued by CreateToken.
+type Claims struct {
+	ID string `json:"id"`
+	// ImpersonatorID is set on impersonation tokens to the administrator
+	// acting as the user identified by ID.
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
+	// Generation is the session generation of the user identified by ID
+	// when the token was issued. Revoking the user's sessions moves it on,
+	// and the token with it.
+	Generation int `json:"gen,omitempty"`
+	// ImpersonatorGeneration is the impersonator's session generation,
+	// so that revoking the administrator's sessions ends the impersonation.
+	ImpersonatorGeneration int `json:"impersonator_gen,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// CreateToken issues a token for the user id at its current session
+// generation.
+func CreateToken(id string, generation int) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		ID:         id,
+		Generation: generation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
+		},
 	})
 
 	tokenString, err := token.SignedString(secret_key)
@@ -24,17 +46,44 @@ func CreateToken(id string) (string, error) {
 	return tokenString, nil
 }
 
-func VerifyToken(tokenString string) error {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+// CreateImpersonationToken issues a short lived token that authenticates
+// actorID as subjectID while keeping track of who is really acting. Both
+// users' session generations are recorded, so revoking either's sessions
+// revokes the token.
+func CreateImpersonationToken(actorID string, actorGeneration int, subjectID string, subjectGeneration int,
+	ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		ID:                     subjectID,
+		Generation:             subjectGeneration,
+		ImpersonatorID:         actorID,
+		ImpersonatorGeneration: actorGeneration,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	tokenString, err := token.SignedString(secret_key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+func VerifyToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return secret_key, nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !token.Valid {
-		return fmt.Errorf("invalid token")
+		return nil, fmt.Errorf("invalid token")
 	}
 
-	return nil
+	return claims, nil
 }
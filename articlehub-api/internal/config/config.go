@@ -0,0 +1,97 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// String returns the value of the environment variable key, or def when unset.
+func String(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// Int returns the environment variable key parsed as an int, or def when
+// unset or invalid.
+func Int(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid value for %s: %q, using %d", key, v, def)
+		return def
+	}
+	return n
+}
+
+// Bool returns the environment variable key parsed as a bool, or def when
+// unset or invalid.
+func Bool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("invalid value for %s: %q, using %t", key, v, def)
+		return def
+	}
+	return b
+}
+
+// Duration returns the environment variable key parsed with
+// time.ParseDuration, or def when unset or invalid.
+func Duration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid value for %s: %q, using %s", key, v, def)
+		return def
+	}
+	return d
+}
+
+// List returns the comma separated environment variable key as a slice of
+// trimmed, non-empty values, or def when unset.
+func List(key string, def []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// IntList returns the comma separated environment variable key as a slice of
+// ints, or def when unset or when any value is invalid.
+func IntList(key string, def []int) []int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	var out []int
+	for _, item := range List(key, nil) {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			log.Printf("invalid value for %s: %q, using %v", key, v, def)
+			return def
+		}
+		out = append(out, n)
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+
+	DiffModeLine = "line"
+	DiffModeWord = "word"
+)
+
+// maxDiffEdits bounds the work done by Diff. Texts that differ by more
+// edits than this are reported as a full replacement of the differing part.
+const maxDiffEdits = 2000
+
+// DiffOp is a run of text that is unchanged, inserted or deleted.
+type DiffOp struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+var wordTokens = regexp.MustCompile(`\s+|[^\s]+`)
+
+// Diff compares two texts line by line (DiffModeLine) or word by word
+// (DiffModeWord) and returns the operations turning a into b.
+func Diff(a, b, mode string) []DiffOp {
+	var ta, tb []string
+	if mode == DiffModeWord {
+		ta, tb = wordTokens.FindAllString(a, -1), wordTokens.FindAllString(b, -1)
+	} else {
+		ta, tb = splitLines(a), splitLines(b)
+	}
+	return diffTokens(ta, tb)
+}
+
+// splitLines splits s into lines, keeping the line terminators so that the
+// operations concatenate back into the original texts.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func diffTokens(a, b []string) []DiffOp {
+	var ops []DiffOp
+
+	// Common prefix and suffix are cheap to find and usually make up most
+	// of the text between two revisions.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops = appendOp(ops, DiffEqual, a[:prefix]...)
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	ops = appendOp(ops, DiffEqual, a[len(a)-suffix:]...)
+	return ops
+}
+
+// myers implements Myers' O(ND) difference algorithm.
+func myers(a, b []string) []DiffOp {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return appendOp(appendOp(nil, DiffDelete, a...), DiffInsert, b...)
+	}
+
+	max := n + m
+	if max > maxDiffEdits {
+		max = maxDiffEdits
+	}
+
+	// v[k] is the furthest x reached on diagonal k; trace keeps a copy of
+	// v, restricted to the diagonals -d..d, before each round d.
+	v := make(map[int]int, 2*max+1)
+	v[1] = 0
+	var trace [][]int
+	found := false
+	for d := 0; d <= max && !found; d++ {
+		snapshot := make([]int, 2*d+3)
+		for k := -d - 1; k <= d+1; k++ {
+			snapshot[k+d+1] = v[k]
+		}
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			} else {
+				x = v[k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return appendOp(appendOp(nil, DiffDelete, a...), DiffInsert, b...)
+	}
+
+	// Walk the trace backwards to recover the edit script.
+	var reversed []DiffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, DiffOp{Op: DiffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, DiffOp{Op: DiffInsert, Text: b[y-1]})
+				y--
+			} else {
+				reversed = append(reversed, DiffOp{Op: DiffDelete, Text: a[x-1]})
+				x--
+			}
+		}
+	}
+
+	var ops []DiffOp
+	for i := len(reversed) - 1; i >= 0; i-- {
+		ops = appendOp(ops, reversed[i].Op, reversed[i].Text)
+	}
+	return ops
+}
+
+// appendOp appends tokens to ops, merging them into the last operation when
+// it is of the same kind.
+func appendOp(ops []DiffOp, op string, tokens ...string) []DiffOp {
+	if len(tokens) == 0 {
+		return ops
+	}
+	text := strings.Join(tokens, "")
+	if len(ops) > 0 && ops[len(ops)-1].Op == op {
+		ops[len(ops)-1].Text += text
+		return ops
+	}
+	return append(ops, DiffOp{Op: op, Text: text})
+}
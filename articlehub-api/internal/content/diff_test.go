@@ -0,0 +1,130 @@
+package content
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		mode string
+		want []DiffOp
+	}{
+		{
+			name: "identical",
+			a:    "one\ntwo\n",
+			b:    "one\ntwo\n",
+			mode: DiffModeLine,
+			want: []DiffOp{{DiffEqual, "one\ntwo\n"}},
+		},
+		{
+			name: "both empty",
+			mode: DiffModeLine,
+			want: nil,
+		},
+		{
+			name: "from empty",
+			b:    "one\n",
+			mode: DiffModeLine,
+			want: []DiffOp{{DiffInsert, "one\n"}},
+		},
+		{
+			name: "to empty",
+			a:    "one\n",
+			mode: DiffModeLine,
+			want: []DiffOp{{DiffDelete, "one\n"}},
+		},
+		{
+			name: "line changed",
+			a:    "one\ntwo\nthree\n",
+			b:    "one\n2\nthree\n",
+			mode: DiffModeLine,
+			want: []DiffOp{{DiffEqual, "one\n"}, {DiffDelete, "two\n"}, {DiffInsert, "2\n"}, {DiffEqual, "three\n"}},
+		},
+		{
+			name: "line inserted",
+			a:    "one\nthree\n",
+			b:    "one\ntwo\nthree\n",
+			mode: DiffModeLine,
+			want: []DiffOp{{DiffEqual, "one\n"}, {DiffInsert, "two\n"}, {DiffEqual, "three\n"}},
+		},
+		{
+			name: "missing final newline",
+			a:    "one\ntwo",
+			b:    "one\ntwo\n",
+			mode: DiffModeLine,
+			want: []DiffOp{{DiffEqual, "one\n"}, {DiffDelete, "two"}, {DiffInsert, "two\n"}},
+		},
+		{
+			name: "word changed",
+			a:    "the quick fox",
+			b:    "the slow fox",
+			mode: DiffModeWord,
+			want: []DiffOp{{DiffEqual, "the "}, {DiffDelete, "quick"}, {DiffInsert, "slow"}, {DiffEqual, " fox"}},
+		},
+		{
+			name: "words moved",
+			a:    "a b c",
+			b:    "c b a",
+			mode: DiffModeWord,
+			want: []DiffOp{{DiffDelete, "a"}, {DiffInsert, "c"}, {DiffEqual, " b "}, {DiffDelete, "c"}, {DiffInsert, "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.a, tt.b, tt.mode)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff(%q, %q, %q) = %v, want %v", tt.a, tt.b, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDiffReassembles checks that the operations of a diff rebuild both
+// texts, including when the edit budget is exhausted.
+func TestDiffReassembles(t *testing.T) {
+	var long, other strings.Builder
+	for i := 0; i < maxDiffEdits; i++ {
+		long.WriteString("a\n")
+		other.WriteString("b\n")
+	}
+
+	tests := []struct {
+		name string
+		a, b string
+		mode string
+	}{
+		{"lines", "title\n\nfirst\nsecond\nthird\n", "title\n\nsecond\nthird\nfourth\n", DiffModeLine},
+		{"words", "it was the best of times", "it was the worst of  times, really", DiffModeWord},
+		{"over budget", long.String(), other.String(), DiffModeLine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a, b strings.Builder
+			for _, op := range Diff(tt.a, tt.b, tt.mode) {
+				switch op.Op {
+				case DiffEqual:
+					a.WriteString(op.Text)
+					b.WriteString(op.Text)
+				case DiffDelete:
+					a.WriteString(op.Text)
+				case DiffInsert:
+					b.WriteString(op.Text)
+				default:
+					t.Fatalf("unknown operation %q", op.Op)
+				}
+			}
+			if a.String() != tt.a {
+				t.Errorf("old text rebuilt as %q, want %q", a.String(), tt.a)
+			}
+			if b.String() != tt.b {
+				t.Errorf("new text rebuilt as %q, want %q", b.String(), tt.b)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package content
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+
+	"articlehub-api/internal/model"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Renderer turns article Markdown into HTML that is safe to serve as is.
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+}
+
+// NewRenderer returns a renderer for CommonMark with the GitHub Flavored
+// Markdown extensions (tables, task lists, strikethrough, autolinks),
+// footnotes, LaTeX math and references to uploaded media. Fenced code
+// blocks are highlighted into spans carrying chroma's token classes; the
+// colours come from the stylesheet served by StyleHandler.
+func NewRenderer() *Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.Linkify,
+			extension.TaskList,
+			extension.Footnote,
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+			mathExtension{},
+		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithASTTransformers(util.Prioritized(mediaTransformer{}, 100)),
+		),
+	)
+
+	return &Renderer{
+		markdown: md,
+		policy:   newPolicy(),
+	}
+}
+
+// Render converts Markdown source to sanitised HTML.
+func (r *Renderer) Render(source string) (string, error) {
+	html, _, err := r.render([]byte(source), nil)
+	return html, err
+}
+
+// RenderArticle converts an article's Markdown to sanitised HTML and computes
+// the statistics and table of contents stored alongside it. Media references
+// are resolved against media, keyed by ID.
+func (r *Renderer) RenderArticle(source string, media map[string]model.Media) (string, model.ArticleStats, error) {
+	src := []byte(source)
+	html, doc, err := r.render(src, media)
+	if err != nil {
+		return "", model.ArticleStats{}, err
+	}
+	return html, analyze(doc, src), nil
+}
+
+func (r *Renderer) render(src []byte, media map[string]model.Media) (string, ast.Node, error) {
+	ctx := parser.NewContext(parser.WithIDs(&headingIDs{used: map[string]bool{}}))
+	ctx.Set(mediaKey, media)
+	doc := r.markdown.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := r.markdown.Renderer().Render(&buf, src, doc); err != nil {
+		return "", nil, err
+	}
+	return r.policy.Sanitize(buf.String()), doc, nil
+}
+
+// headingIDs gives headings anchor IDs derived from their text the same way
+// slugs are, so they stay stable across edits that do not touch the heading.
+// Repeated headings get -2, -3, ... appended.
+type headingIDs struct {
+	used map[string]bool
+}
+
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := Slugify(string(value))
+	if base == "" {
+		base = "section"
+	}
+	id := base
+	for n := 2; ids.used[id]; n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+	ids.used[id] = true
+	return []byte(id)
+}
+
+func (ids *headingIDs) Put(value []byte) {
+	ids.used[string(value)] = true
+}
+
+// newPolicy builds the allowlist applied to rendered HTML. It starts from
+// bluemonday's user generated content policy, which already strips scripts,
+// event handlers and unsafe URLs, and lets through the markup produced by
+// the Markdown extensions we enable.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	// Fenced code block languages
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+
+	// Highlighted code: chroma token classes and the math display wrapper
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[\w -]+$`)).OnElements("pre", "span", "div")
+
+	// MathML produced for $...$ and $$...$$
+	p.AllowNoAttrs().OnElements("math", "semantics", "annotation", "mrow", "mi", "mn", "mo", "mtext",
+		"msup", "msub", "msubsup", "mfrac", "msqrt", "mroot", "mover", "munder", "munderover",
+		"mspace", "mtable", "mtr", "mtd")
+	p.AllowAttrs("xmlns").Matching(regexp.MustCompile(`^http://www\.w3\.org/1998/Math/MathML$`)).OnElements("math")
+	p.AllowAttrs("display").Matching(regexp.MustCompile(`^(block|inline)$`)).OnElements("math")
+	p.AllowAttrs("encoding").Matching(regexp.MustCompile(`^application/x-tex$`)).OnElements("annotation")
+	p.AllowAttrs("mathvariant", "stretchy", "fence", "largeop", "accent", "linethickness", "width", "columnalign").
+		Matching(regexp.MustCompile(`^[\w .-]+$`)).
+		OnElements("mi", "mn", "mo", "mtext", "mfrac", "mover", "munder", "munderover", "mspace", "mtable")
+
+	// Responsive media images, whose alt text comes from the media library
+	// and may hold any punctuation; it is escaped on output
+	p.AllowAttrs("alt").Matching(regexp.MustCompile(`^[^<>]*$`)).OnElements("img")
+	p.AllowAttrs("srcset").Matching(regexp.MustCompile(`^https?://[^\s,]+ \d+w(, https?://[^\s,]+ \d+w)*$`)).OnElements("img")
+	p.AllowAttrs("sizes").Matching(regexp.MustCompile(`^[\w\s(),:.-]+$`)).OnElements("img")
+	p.AllowAttrs("loading").Matching(regexp.MustCompile(`^lazy$`)).OnElements("img")
+	p.AllowAttrs("decoding").Matching(regexp.MustCompile(`^async$`)).OnElements("img")
+
+	// Heading anchors are slugs, which keep the letters of scripts without
+	// an ASCII spelling
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}\p{M}-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+
+	// Table column alignment
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+
+	// Task list items render as disabled checkboxes
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").Matching(regexp.MustCompile(`^$`)).OnElements("input")
+
+	// Footnote references and back links
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^(footnote-ref|footnote-backref|footnotes)$`)).OnElements("a", "div")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|backlink|endnotes)$`)).OnElements("a", "div")
+
+	return p
+}
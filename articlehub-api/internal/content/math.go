@@ -0,0 +1,211 @@
+package content
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// mathExtension adds LaTeX math to Markdown: $...$ inline, and $$...$$
+// either inline or as a block of its own. Formulas are rendered to MathML;
+// those outside the supported subset are shown as LaTeX source in a code
+// element.
+type mathExtension struct{}
+
+func (mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(mathBlockParser{}, 650)),
+		parser.WithInlineParsers(util.Prioritized(mathInlineParser{}, 150)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(mathRenderer{}, 500)))
+}
+
+var (
+	KindMath      = ast.NewNodeKind("Math")
+	KindMathBlock = ast.NewNodeKind("MathBlock")
+)
+
+// Math is an inline formula.
+type Math struct {
+	ast.BaseInline
+	Source  string
+	Display bool
+}
+
+func (n *Math) Kind() ast.NodeKind { return KindMath }
+
+func (n *Math) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Source": n.Source}, nil)
+}
+
+// MathBlock is a displayed formula between lines of $$.
+type MathBlock struct {
+	ast.BaseBlock
+	// closed is set when the formula ends on its opening line.
+	closed bool
+}
+
+func (n *MathBlock) Kind() ast.NodeKind { return KindMathBlock }
+
+func (n *MathBlock) IsRaw() bool { return true }
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type mathInlineParser struct{}
+
+func (mathInlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse follows Pandoc's rules so that prices are not mistaken for math: the
+// opening $ must be followed by a non-space, the closing $ preceded by a
+// non-space and not followed by a digit.
+func (mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	delimiter := []byte("$")
+	if bytes.HasPrefix(line, []byte("$$")) {
+		delimiter = []byte("$$")
+	}
+	body := line[len(delimiter):]
+	if len(body) == 0 || body[0] == ' ' || body[0] == '\t' {
+		return nil
+	}
+
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\':
+			i++
+		case bytes.HasPrefix(body[i:], delimiter):
+			if i == 0 || body[i-1] == ' ' || body[i-1] == '\t' {
+				return nil
+			}
+			after := i + len(delimiter)
+			if len(delimiter) == 1 && after < len(body) && '0' <= body[after] && body[after] <= '9' {
+				return nil
+			}
+			block.Advance(len(delimiter) + after)
+			return &Math{Source: string(body[:i]), Display: len(delimiter) == 2}
+		}
+	}
+	return nil
+}
+
+type mathBlockParser struct{}
+
+func (mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+	rest := util.TrimRightSpace(line[pos+2:])
+	start := segment.Start + pos + 2
+
+	node := &MathBlock{}
+	if i := bytes.Index(rest, []byte("$$")); i >= 0 {
+		// A formula on a single line is only a block when nothing follows
+		// it; otherwise it is display math inside a paragraph.
+		if len(util.TrimLeftSpace(rest[i+2:])) > 0 {
+			return nil, parser.NoChildren
+		}
+		node.Lines().Append(text.NewSegment(start, start+i))
+		node.closed = true
+		advanceLine(reader, line, segment)
+		return node, parser.NoChildren
+	}
+	if len(util.TrimLeftSpace(rest)) > 0 {
+		node.Lines().Append(text.NewSegment(start, start+len(rest)))
+	}
+	advanceLine(reader, line, segment)
+	return node, parser.NoChildren
+}
+
+func (mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	if node.(*MathBlock).closed {
+		return parser.Close
+	}
+	line, segment := reader.PeekLine()
+	trimmed := util.TrimRightSpace(line)
+	if i := bytes.Index(trimmed, []byte("$$")); i >= 0 && len(util.TrimLeftSpace(trimmed[i+2:])) == 0 {
+		node.Lines().Append(text.NewSegment(segment.Start, segment.Start+i))
+		advanceLine(reader, line, segment)
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	advanceLine(reader, line, segment)
+	return parser.Continue | parser.NoChildren
+}
+
+func (mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+// advanceLine consumes the line peeked from reader, except for its newline,
+// which is left for the block parser. The last line of the input may have
+// none.
+func advanceLine(reader text.Reader, line []byte, segment text.Segment) {
+	n := segment.Len()
+	if bytes.HasSuffix(line, []byte("\n")) {
+		n--
+	}
+	reader.Advance(n)
+}
+
+func (mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type mathRenderer struct{}
+
+func (mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, renderMath)
+	reg.Register(KindMathBlock, renderMathBlock)
+}
+
+func renderMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*Math)
+		writeMath(w, n.Source, n.Display)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var tex bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		tex.Write(segment.Value(source))
+	}
+	w.WriteString(`<div class="math-display">`)
+	writeMath(w, string(bytes.TrimSpace(tex.Bytes())), true)
+	w.WriteString("</div>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func writeMath(w util.BufWriter, tex string, display bool) {
+	mathML, err := LaTeXToMathML(tex, display)
+	if err != nil {
+		w.WriteString(`<code class="language-latex">`)
+		w.Write(util.EscapeHTML([]byte(tex)))
+		w.WriteString("</code>")
+		return
+	}
+	w.WriteString(mathML)
+}
@@ -0,0 +1,49 @@
+package content
+
+import "testing"
+
+func TestRenderMath(t *testing.T) {
+	const (
+		x      = `<math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mi>x</mi><annotation encoding="application/x-tex">x</annotation></semantics></math>`
+		xBlock = `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><mi>x</mi><annotation encoding="application/x-tex">x</annotation></semantics></math>`
+		yBlock = `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><mi>y</mi><annotation encoding="application/x-tex">y</annotation></semantics></math>`
+		x2     = `<math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><msup><mi>x</mi><mn>2</mn></msup><annotation encoding="application/x-tex">x^2</annotation></semantics></math>`
+	)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"block", "$$\nx^2\n$$\n", `<div class="math-display">` + x2 + "</div>\n"},
+		{"block at end of input", "$$\nx^2\n$$", `<div class="math-display">` + x2 + "</div>\n"},
+		{"block opened with formula at end of input", "$$x^2\n$$", `<div class="math-display">` + x2 + "</div>\n"},
+		{"single line block", "$$x$$", `<div class="math-display">` + xBlock + "</div>\n"},
+		{"unclosed block", "$$\nx\n", `<div class="math-display">` + xBlock + "</div>\n"},
+		{"block followed by paragraph", "$$\nx\n$$\n\nafter", `<div class="math-display">` + xBlock + "</div>\n<p>after</p>\n"},
+		{"block interrupting paragraph", "text\n$$\nx\n$$", "<p>text</p>\n" + `<div class="math-display">` + xBlock + "</div>\n"},
+		{
+			"block ending list item at end of input",
+			"- item\n\n  $$\n  x\n  $$",
+			"<ul>\n<li>\n<p>item</p>\n" + `<div class="math-display">` + xBlock + "</div>\n</li>\n</ul>\n",
+		},
+		{"unsupported block", "$$ \\foo $$", `<div class="math-display"><code class="language-latex">\foo</code></div>` + "\n"},
+		{"inline", "$x$ and $$y$$ inline", "<p>" + x + " and " + yBlock + " inline</p>\n"},
+		{"prices", "price $5 and $6", "<p>price $5 and $6</p>\n"},
+		{"digit after closing", "a $x$5", "<p>a $x$5</p>\n"},
+		{"space after opening", "$ x$", "<p>$ x$</p>\n"},
+	}
+
+	r := NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Render(tt.in)
+			if err != nil {
+				t.Fatalf("Render(%q) failed: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
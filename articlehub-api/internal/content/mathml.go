@@ -0,0 +1,650 @@
+package content
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits keeping pathological formulas from tying up the renderer.
+const (
+	maxMathLength = 4096
+	maxMathDepth  = 64
+)
+
+// LaTeXToMathML converts a LaTeX formula to a MathML <math> element, with
+// the source kept as an annotation. It understands the commonly used subset
+// of LaTeX math: scripts, fractions, roots, Greek letters and symbols,
+// function names, accents, font styles, \left/\right delimiters and the
+// matrix, cases and aligned environments. Anything else is reported as an
+// error so that the caller can fall back to showing the source.
+func LaTeXToMathML(source string, display bool) (string, error) {
+	if len(source) > maxMathLength {
+		return "", fmt.Errorf("formula longer than %d bytes", maxMathLength)
+	}
+	p := &mathParser{source: source, tokens: tokenizeMath(source), display: display}
+	body, err := p.parseList("")
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.tokens) {
+		return "", fmt.Errorf("unexpected %q", p.tokens[p.pos].value)
+	}
+
+	var b strings.Builder
+	b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML"`)
+	if display {
+		b.WriteString(` display="block"`)
+	}
+	b.WriteString(`><semantics>`)
+	row(body).write(&b)
+	b.WriteString(`<annotation encoding="application/x-tex">`)
+	b.WriteString(html.EscapeString(source))
+	b.WriteString(`</annotation></semantics></math>`)
+	return b.String(), nil
+}
+
+type mathTokenKind int
+
+const (
+	tokCommand mathTokenKind = iota
+	tokNumber
+	tokLetter
+	tokSymbol
+)
+
+type mathToken struct {
+	kind  mathTokenKind
+	value string
+	// pos is the offset of the token in the source.
+	pos int
+}
+
+func tokenizeMath(s string) []mathToken {
+	var tokens []mathToken
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '\\':
+			j := i + 1
+			for j < len(s) && isASCIILetter(s[j]) {
+				j++
+			}
+			if j == i+1 && j < len(s) {
+				_, n := utf8.DecodeRuneInString(s[j:])
+				j += n
+			}
+			tokens = append(tokens, mathToken{tokCommand, s[i:j], i})
+			i = j
+		case '0' <= r && r <= '9' || r == '.' && i+1 < len(s) && '0' <= s[i+1] && s[i+1] <= '9':
+			j := i
+			for j < len(s) && ('0' <= s[j] && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, mathToken{tokNumber, s[i:j], i})
+			i = j
+		case unicode.IsLetter(r):
+			tokens = append(tokens, mathToken{tokLetter, s[i : i+size], i})
+			i += size
+		default:
+			tokens = append(tokens, mathToken{tokSymbol, s[i : i+size], i})
+			i += size
+		}
+	}
+	return tokens
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// mnode is a MathML element. Token elements (mi, mn, mo, mtext) carry text,
+// the others children.
+type mnode struct {
+	tag      string
+	attrs    [][2]string
+	text     string
+	children []*mnode
+}
+
+func token(tag, text string, attrs ...[2]string) *mnode {
+	return &mnode{tag: tag, text: text, attrs: attrs}
+}
+
+func elem(tag string, children ...*mnode) *mnode {
+	return &mnode{tag: tag, children: children}
+}
+
+// row groups nodes into a single one.
+func row(nodes []*mnode) *mnode {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return elem("mrow", nodes...)
+}
+
+func (n *mnode) write(b *strings.Builder) {
+	b.WriteString("<" + n.tag)
+	for _, attr := range n.attrs {
+		b.WriteString(" " + attr[0] + `="` + html.EscapeString(attr[1]) + `"`)
+	}
+	b.WriteString(">")
+	b.WriteString(html.EscapeString(n.text))
+	for _, child := range n.children {
+		child.write(b)
+	}
+	b.WriteString("</" + n.tag + ">")
+}
+
+// setVariant applies a font style to every identifier below n.
+func (n *mnode) setVariant(variant string) {
+	if n.tag == "mi" || n.tag == "mn" {
+		n.attrs = append(n.attrs, [2]string{"mathvariant", variant})
+	}
+	for _, child := range n.children {
+		child.setVariant(variant)
+	}
+}
+
+type mathParser struct {
+	source  string
+	tokens  []mathToken
+	pos     int
+	depth   int
+	display bool
+}
+
+func (p *mathParser) peek() *mathToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *mathParser) next() *mathToken {
+	t := p.peek()
+	if t != nil {
+		p.pos++
+	}
+	return t
+}
+
+func (p *mathParser) atEnd(terminator string) bool {
+	t := p.peek()
+	if t == nil {
+		return true
+	}
+	if terminator == "" {
+		return false
+	}
+	// Table cells also end at the next cell or row.
+	return t.value == terminator || terminator == `\end` && (t.value == "&" || t.value == `\\`)
+}
+
+// parseList parses atoms up to, not including, the terminator token. An
+// empty terminator parses to the end of the input.
+func (p *mathParser) parseList(terminator string) ([]*mnode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxMathDepth {
+		return nil, fmt.Errorf("formula nested too deeply")
+	}
+
+	var nodes []*mnode
+	for !p.atEnd(terminator) {
+		if t := p.peek(); t.value == "}" || t.value == `\right` || t.value == `\end` {
+			return nil, fmt.Errorf("unexpected %q", t.value)
+		}
+		node, err := p.parseScripted()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	if terminator != "" && p.peek() == nil {
+		return nil, fmt.Errorf("missing %q", terminator)
+	}
+	return nodes, nil
+}
+
+// parseScripted parses an atom followed by any sub- and superscripts.
+func (p *mathParser) parseScripted() (*mnode, error) {
+	base, limits, err := p.parseAtom()
+	if err != nil || base == nil {
+		return base, err
+	}
+
+	under := limits && p.display
+	var sub, sup *mnode
+	for t := p.peek(); t != nil; t = p.peek() {
+		switch t.value {
+		case `\limits`, `\nolimits`:
+			p.next()
+			under = t.value == `\limits`
+			continue
+		case "_", "^":
+			p.next()
+			script, err := p.parseArgument()
+			if err != nil {
+				return nil, err
+			}
+			if t.value == "_" && sub == nil {
+				sub = script
+			} else if t.value == "^" && sup == nil {
+				sup = script
+			} else {
+				return nil, fmt.Errorf("double %s", t.value)
+			}
+			continue
+		case "'":
+			p.next()
+			primes := "′"
+			for next := p.peek(); next != nil && next.value == "'"; next = p.peek() {
+				p.next()
+				primes += "′"
+			}
+			if sup != nil {
+				return nil, fmt.Errorf("double ^")
+			}
+			sup = token("mo", primes)
+			continue
+		}
+		break
+	}
+
+	switch {
+	case sub != nil && sup != nil && under:
+		return elem("munderover", base, sub, sup), nil
+	case sub != nil && sup != nil:
+		return elem("msubsup", base, sub, sup), nil
+	case sub != nil && under:
+		return elem("munder", base, sub), nil
+	case sub != nil:
+		return elem("msub", base, sub), nil
+	case sup != nil && under:
+		return elem("mover", base, sup), nil
+	case sup != nil:
+		return elem("msup", base, sup), nil
+	}
+	return base, nil
+}
+
+// parseArgument parses the argument of a command or script: a braced group
+// or a single token.
+func (p *mathParser) parseArgument() (*mnode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("missing argument")
+	}
+	if t.value == "{" {
+		p.next()
+		nodes, err := p.parseList("}")
+		if err != nil {
+			return nil, err
+		}
+		p.next()
+		return elem("mrow", nodes...), nil
+	}
+	node, _, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("missing argument")
+	}
+	return node, nil
+}
+
+// rawArgument returns the source text of a braced argument, for commands
+// such as \text whose argument is not math.
+func (p *mathParser) rawArgument() (string, error) {
+	open := p.next()
+	if open == nil || open.value != "{" {
+		return "", fmt.Errorf("missing argument")
+	}
+	depth := 0
+	for t := p.next(); t != nil; t = p.next() {
+		switch t.value {
+		case "{":
+			depth++
+		case "}":
+			if depth == 0 {
+				return p.source[open.pos+1 : t.pos], nil
+			}
+			depth--
+		}
+	}
+	return "", fmt.Errorf("missing %q", "}")
+}
+
+// parseAtom parses one element. limits reports whether scripts go above and
+// below it in display mode, as for \sum or \lim.
+func (p *mathParser) parseAtom() (node *mnode, limits bool, err error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return token("mn", t.value), false, nil
+	case tokLetter:
+		return token("mi", t.value), false, nil
+	case tokSymbol:
+		switch t.value {
+		case "{":
+			nodes, err := p.parseList("}")
+			if err != nil {
+				return nil, false, err
+			}
+			p.next()
+			return elem("mrow", nodes...), false, nil
+		case "^", "_":
+			return nil, false, fmt.Errorf("script without base")
+		case "&":
+			return nil, false, fmt.Errorf("unexpected &")
+		case "~":
+			return token("mtext", " "), false, nil
+		}
+		if strings.Contains("()[]|", t.value) {
+			return token("mo", t.value, [2]string{"stretchy", "false"}), false, nil
+		}
+		return token("mo", t.value), false, nil
+	}
+	return p.parseCommand(t.value)
+}
+
+func (p *mathParser) parseCommand(name string) (*mnode, bool, error) {
+	if s, ok := mathIdentifiers[name]; ok {
+		if r, _ := utf8.DecodeRuneInString(s); unicode.IsUpper(r) {
+			return token("mi", s, [2]string{"mathvariant", "normal"}), false, nil
+		}
+		return token("mi", s), false, nil
+	}
+	if s, ok := mathOperators[name]; ok {
+		return token("mo", s), false, nil
+	}
+	if s, ok := mathLargeOperators[name]; ok {
+		// Integrals keep their limits beside the sign.
+		return token("mo", s, [2]string{"largeop", "true"}), !strings.Contains(s, "∫") && !strings.Contains(s, "∮"), nil
+	}
+	if mathFunctions[name] {
+		return token("mi", name[1:]), mathLimitFunctions[name], nil
+	}
+	if width, ok := mathSpaces[name]; ok {
+		return &mnode{tag: "mspace", attrs: [][2]string{{"width", width}}}, false, nil
+	}
+	if accent, ok := mathAccents[name]; ok {
+		base, err := p.parseArgument()
+		if err != nil {
+			return nil, false, err
+		}
+		if name == `\underline` {
+			return elem("munder", base, token("mo", accent)), false, nil
+		}
+		n := elem("mover", base, token("mo", accent))
+		n.attrs = [][2]string{{"accent", "true"}}
+		return n, false, nil
+	}
+	if variant, ok := mathVariants[name]; ok {
+		arg, err := p.parseArgument()
+		if err != nil {
+			return nil, false, err
+		}
+		arg.setVariant(variant)
+		return arg, false, nil
+	}
+	if mathSizes[name] {
+		// Delimiter sizes are left to the renderer.
+		return nil, false, nil
+	}
+
+	switch name {
+	case `\frac`, `\dfrac`, `\tfrac`, `\binom`:
+		num, err := p.parseArgument()
+		if err != nil {
+			return nil, false, err
+		}
+		den, err := p.parseArgument()
+		if err != nil {
+			return nil, false, err
+		}
+		if name != `\binom` {
+			return elem("mfrac", num, den), false, nil
+		}
+		frac := elem("mfrac", num, den)
+		frac.attrs = [][2]string{{"linethickness", "0"}}
+		return elem("mrow", token("mo", "("), frac, token("mo", ")")), false, nil
+	case `\sqrt`:
+		var index *mnode
+		if t := p.peek(); t != nil && t.value == "[" {
+			p.next()
+			nodes, err := p.parseList("]")
+			if err != nil {
+				return nil, false, err
+			}
+			p.next()
+			index = row(nodes)
+		}
+		radicand, err := p.parseArgument()
+		if err != nil {
+			return nil, false, err
+		}
+		if index != nil {
+			return elem("mroot", radicand, index), false, nil
+		}
+		return elem("msqrt", radicand), false, nil
+	case `\text`, `\textrm`, `\textit`, `\textbf`, `\mbox`:
+		text, err := p.rawArgument()
+		if err != nil {
+			return nil, false, err
+		}
+		return token("mtext", text), false, nil
+	case `\operatorname`:
+		text, err := p.rawArgument()
+		if err != nil {
+			return nil, false, err
+		}
+		return token("mi", strings.TrimSpace(text)), false, nil
+	case `\left`:
+		return p.parseFenced()
+	case `\begin`:
+		return p.parseEnvironment()
+	}
+	return nil, false, fmt.Errorf("unsupported command %s", name)
+}
+
+// parseFenced parses \left<delim> ... \right<delim>.
+func (p *mathParser) parseFenced() (*mnode, bool, error) {
+	open, err := p.delimiter()
+	if err != nil {
+		return nil, false, err
+	}
+	nodes, err := p.parseList(`\right`)
+	if err != nil {
+		return nil, false, err
+	}
+	p.next()
+	closing, err := p.delimiter()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var children []*mnode
+	if open != "" {
+		children = append(children, token("mo", open, [2]string{"fence", "true"}, [2]string{"stretchy", "true"}))
+	}
+	children = append(children, nodes...)
+	if closing != "" {
+		children = append(children, token("mo", closing, [2]string{"fence", "true"}, [2]string{"stretchy", "true"}))
+	}
+	return elem("mrow", children...), false, nil
+}
+
+func (p *mathParser) delimiter() (string, error) {
+	t := p.next()
+	if t == nil {
+		return "", fmt.Errorf("missing delimiter")
+	}
+	if t.value == "." {
+		return "", nil
+	}
+	if t.kind == tokSymbol && strings.Contains("()[]|/", t.value) {
+		return t.value, nil
+	}
+	if s, ok := mathOperators[t.value]; ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid delimiter %q", t.value)
+}
+
+// Fences around the matrix environments.
+var mathEnvironmentFences = map[string][2]string{
+	"matrix":   {"", ""},
+	"pmatrix":  {"(", ")"},
+	"bmatrix":  {"[", "]"},
+	"Bmatrix":  {"{", "}"},
+	"vmatrix":  {"|", "|"},
+	"Vmatrix":  {"‖", "‖"},
+	"cases":    {"{", ""},
+	"aligned":  {"", ""},
+	"align":    {"", ""},
+	"align*":   {"", ""},
+	"gathered": {"", ""},
+}
+
+// parseEnvironment parses \begin{name} ... \end{name} into a table, rows
+// separated by \\ and cells by &.
+func (p *mathParser) parseEnvironment() (*mnode, bool, error) {
+	name, err := p.rawArgument()
+	if err != nil {
+		return nil, false, err
+	}
+	name = strings.TrimSpace(name)
+	fences, ok := mathEnvironmentFences[name]
+	if !ok {
+		return nil, false, fmt.Errorf("unsupported environment %s", name)
+	}
+
+	table := elem("mtable")
+	current := elem("mtr")
+	for {
+		cell, err := p.parseList(`\end`)
+		if err != nil {
+			return nil, false, err
+		}
+		current.children = append(current.children, elem("mtd", cell...))
+
+		switch p.next().value {
+		case "&":
+			continue
+		case `\\`:
+			table.children = append(table.children, current)
+			current = elem("mtr")
+			continue
+		}
+		// \end
+		table.children = append(table.children, current)
+		end, err := p.rawArgument()
+		if err != nil {
+			return nil, false, err
+		}
+		if strings.TrimSpace(end) != name {
+			return nil, false, fmt.Errorf("\\begin{%s} ended by \\end{%s}", name, end)
+		}
+		break
+	}
+
+	switch name {
+	case "cases":
+		table.attrs = [][2]string{{"columnalign", "left left"}}
+	case "aligned", "align", "align*":
+		table.attrs = [][2]string{{"columnalign", "right left right left"}}
+	}
+	if fences[0] == "" && fences[1] == "" {
+		return table, false, nil
+	}
+	children := []*mnode{}
+	if fences[0] != "" {
+		children = append(children, token("mo", fences[0], [2]string{"fence", "true"}))
+	}
+	children = append(children, table)
+	if fences[1] != "" {
+		children = append(children, token("mo", fences[1], [2]string{"fence", "true"}))
+	}
+	return elem("mrow", children...), false, nil
+}
+
+var mathIdentifiers = map[string]string{
+	`\alpha`: "α", `\beta`: "β", `\gamma`: "γ", `\delta`: "δ", `\epsilon`: "ϵ", `\varepsilon`: "ε",
+	`\zeta`: "ζ", `\eta`: "η", `\theta`: "θ", `\vartheta`: "ϑ", `\iota`: "ι", `\kappa`: "κ",
+	`\lambda`: "λ", `\mu`: "μ", `\nu`: "ν", `\xi`: "ξ", `\pi`: "π", `\varpi`: "ϖ", `\rho`: "ρ",
+	`\varrho`: "ϱ", `\sigma`: "σ", `\varsigma`: "ς", `\tau`: "τ", `\upsilon`: "υ", `\phi`: "ϕ",
+	`\varphi`: "φ", `\chi`: "χ", `\psi`: "ψ", `\omega`: "ω",
+	`\Gamma`: "Γ", `\Delta`: "Δ", `\Theta`: "Θ", `\Lambda`: "Λ", `\Xi`: "Ξ", `\Pi`: "Π",
+	`\Sigma`: "Σ", `\Upsilon`: "Υ", `\Phi`: "Φ", `\Psi`: "Ψ", `\Omega`: "Ω",
+	`\infty`: "∞", `\partial`: "∂", `\nabla`: "∇", `\emptyset`: "∅", `\varnothing`: "∅",
+	`\hbar`: "ℏ", `\ell`: "ℓ", `\Re`: "ℜ", `\Im`: "ℑ", `\aleph`: "ℵ",
+}
+
+var mathOperators = map[string]string{
+	`\+`: "+", `\{`: "{", `\}`: "}", `\|`: "‖", `\%`: "%", `\$`: "$", `\#`: "#", `\&`: "&", `\_`: "_",
+	`\times`: "×", `\cdot`: "⋅", `\pm`: "±", `\mp`: "∓", `\div`: "÷", `\ast`: "∗", `\star`: "⋆",
+	`\circ`: "∘", `\bullet`: "∙", `\oplus`: "⊕", `\otimes`: "⊗",
+	`\leq`: "≤", `\le`: "≤", `\geq`: "≥", `\ge`: "≥", `\neq`: "≠", `\ne`: "≠", `\ll`: "≪", `\gg`: "≫",
+	`\approx`: "≈", `\equiv`: "≡", `\sim`: "∼", `\simeq`: "≃", `\cong`: "≅", `\propto`: "∝",
+	`\in`: "∈", `\notin`: "∉", `\ni`: "∋", `\subset`: "⊂", `\subseteq`: "⊆", `\supset`: "⊃",
+	`\supseteq`: "⊇", `\cup`: "∪", `\cap`: "∩", `\setminus`: "∖",
+	`\to`: "→", `\rightarrow`: "→", `\leftarrow`: "←", `\gets`: "←", `\leftrightarrow`: "↔",
+	`\Rightarrow`: "⇒", `\Leftarrow`: "⇐", `\Leftrightarrow`: "⇔", `\implies`: "⟹", `\iff`: "⟺",
+	`\mapsto`: "↦", `\uparrow`: "↑", `\downarrow`: "↓",
+	`\forall`: "∀", `\exists`: "∃", `\neg`: "¬", `\lnot`: "¬", `\land`: "∧", `\wedge`: "∧",
+	`\lor`: "∨", `\vee`: "∨",
+	`\ldots`: "…", `\dots`: "…", `\cdots`: "⋯", `\vdots`: "⋮", `\ddots`: "⋱",
+	`\prime`: "′", `\angle`: "∠", `\perp`: "⊥", `\parallel`: "∥", `\mid`: "∣",
+	`\langle`: "⟨", `\rangle`: "⟩", `\lfloor`: "⌊", `\rfloor`: "⌋", `\lceil`: "⌈", `\rceil`: "⌉",
+	`\vert`: "|", `\Vert`: "‖",
+}
+
+var mathLargeOperators = map[string]string{
+	`\sum`: "∑", `\prod`: "∏", `\coprod`: "∐", `\int`: "∫", `\iint`: "∬", `\iiint`: "∭",
+	`\oint`: "∮", `\bigcup`: "⋃", `\bigcap`: "⋂", `\bigoplus`: "⨁", `\bigotimes`: "⨂",
+}
+
+var mathFunctions = map[string]bool{
+	`\sin`: true, `\cos`: true, `\tan`: true, `\cot`: true, `\sec`: true, `\csc`: true,
+	`\arcsin`: true, `\arccos`: true, `\arctan`: true, `\sinh`: true, `\cosh`: true, `\tanh`: true,
+	`\log`: true, `\ln`: true, `\lg`: true, `\exp`: true, `\det`: true, `\dim`: true, `\ker`: true,
+	`\deg`: true, `\arg`: true, `\gcd`: true, `\Pr`: true,
+	`\lim`: true, `\limsup`: true, `\liminf`: true, `\max`: true, `\min`: true, `\sup`: true, `\inf`: true,
+}
+
+// mathLimitFunctions take their subscript below in display mode.
+var mathLimitFunctions = map[string]bool{
+	`\lim`: true, `\limsup`: true, `\liminf`: true, `\max`: true, `\min`: true, `\sup`: true, `\inf`: true,
+	`\det`: true, `\gcd`: true, `\Pr`: true,
+}
+
+var mathSpaces = map[string]string{
+	`\,`: "0.167em", `\:`: "0.222em", `\>`: "0.222em", `\;`: "0.278em", `\!`: "-0.167em",
+	`\ `: "0.25em", `\quad`: "1em", `\qquad`: "2em",
+}
+
+var mathAccents = map[string]string{
+	`\hat`: "^", `\widehat`: "^", `\bar`: "¯", `\overline`: "¯", `\vec`: "→", `\dot`: "˙",
+	`\ddot`: "¨", `\tilde`: "~", `\widetilde`: "~", `\underline`: "_",
+}
+
+var mathVariants = map[string]string{
+	`\mathrm`: "normal", `\mathbf`: "bold", `\mathit`: "italic", `\mathbb`: "double-struck",
+	`\mathcal`: "script", `\mathfrak`: "fraktur", `\mathsf`: "sans-serif", `\mathtt`: "monospace",
+	`\boldsymbol`: "bold-italic",
+}
+
+var mathSizes = map[string]bool{
+	`\big`: true, `\Big`: true, `\bigg`: true, `\Bigg`: true,
+	`\bigl`: true, `\bigr`: true, `\Bigl`: true, `\Bigr`: true,
+	`\displaystyle`: true, `\textstyle`: true,
+}
@@ -0,0 +1,153 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+// mathBody returns the MathML of a formula without the surrounding <math>
+// and <semantics> elements and the annotation.
+func mathBody(t *testing.T, mathML string) string {
+	t.Helper()
+	start := strings.Index(mathML, "<semantics>")
+	end := strings.Index(mathML, "<annotation")
+	if start < 0 || end < start {
+		t.Fatalf("unexpected MathML %s", mathML)
+	}
+	return mathML[start+len("<semantics>") : end]
+}
+
+func TestLaTeXToMathML(t *testing.T) {
+	tests := []struct {
+		tex  string
+		want string
+	}{
+		{`x`, `<mi>x</mi>`},
+		{`12.5`, `<mn>12.5</mn>`},
+		{`a+b=c`, `<mrow><mi>a</mi><mo>+</mo><mi>b</mi><mo>=</mo><mi>c</mi></mrow>`},
+		{`a < b`, `<mrow><mi>a</mi><mo>&lt;</mo><mi>b</mi></mrow>`},
+		{`{a}`, `<mrow><mi>a</mi></mrow>`},
+		{`\{x\}`, `<mrow><mo>{</mo><mi>x</mi><mo>}</mo></mrow>`},
+
+		// Scripts
+		{`x^2`, `<msup><mi>x</mi><mn>2</mn></msup>`},
+		{`x_i`, `<msub><mi>x</mi><mi>i</mi></msub>`},
+		{`x_i^2`, `<msubsup><mi>x</mi><mi>i</mi><mn>2</mn></msubsup>`},
+		{`x^{2n}`, `<msup><mi>x</mi><mrow><mn>2</mn><mi>n</mi></mrow></msup>`},
+		{`x'`, `<msup><mi>x</mi><mo>′</mo></msup>`},
+
+		// Symbols
+		{`\alpha\beta`, `<mrow><mi>α</mi><mi>β</mi></mrow>`},
+		{`\Gamma`, `<mi mathvariant="normal">Γ</mi>`},
+		{`a \quad b`, `<mrow><mi>a</mi><mspace width="1em"></mspace><mi>b</mi></mrow>`},
+
+		// Fractions and roots
+		{`\frac{a}{b}`, `<mfrac><mrow><mi>a</mi></mrow><mrow><mi>b</mi></mrow></mfrac>`},
+		{`\binom{n}{k}`, `<mrow><mo>(</mo><mfrac linethickness="0"><mrow><mi>n</mi></mrow><mrow><mi>k</mi></mrow></mfrac><mo>)</mo></mrow>`},
+		{`\sqrt{x}`, `<msqrt><mrow><mi>x</mi></mrow></msqrt>`},
+		{`\sqrt[3]{x}`, `<mroot><mrow><mi>x</mi></mrow><mn>3</mn></mroot>`},
+
+		// Large operators and functions
+		{`\sum_{i=1}^n i`, `<mrow><msubsup><mo largeop="true">∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></msubsup><mi>i</mi></mrow>`},
+		{`\int_0^1 f`, `<mrow><msubsup><mo largeop="true">∫</mo><mn>0</mn><mn>1</mn></msubsup><mi>f</mi></mrow>`},
+		{`\lim_{x\to 0} x`, `<mrow><msub><mi>lim</mi><mrow><mi>x</mi><mo>→</mo><mn>0</mn></mrow></msub><mi>x</mi></mrow>`},
+		{`\sin x`, `<mrow><mi>sin</mi><mi>x</mi></mrow>`},
+		{`\operatorname{sgn} x`, `<mrow><mi>sgn</mi><mi>x</mi></mrow>`},
+
+		// Accents, fonts and text
+		{`\hat{x}`, `<mover accent="true"><mrow><mi>x</mi></mrow><mo>^</mo></mover>`},
+		{`\underline{x}`, `<munder><mrow><mi>x</mi></mrow><mo>_</mo></munder>`},
+		{`\mathbf{v}`, `<mrow><mi mathvariant="bold">v</mi></mrow>`},
+		{`\text{if } x`, `<mrow><mtext>if </mtext><mi>x</mi></mrow>`},
+
+		// Delimiters
+		{`\left( x \right)`, `<mrow><mo fence="true" stretchy="true">(</mo><mi>x</mi><mo fence="true" stretchy="true">)</mo></mrow>`},
+		{`\left. x \right|`, `<mrow><mi>x</mi><mo fence="true" stretchy="true">|</mo></mrow>`},
+		{`\bigl( x \bigr)`, `<mrow><mo stretchy="false">(</mo><mi>x</mi><mo stretchy="false">)</mo></mrow>`},
+
+		// Environments
+		{
+			`\begin{pmatrix} a & b \\ c & d \end{pmatrix}`,
+			`<mrow><mo fence="true">(</mo><mtable><mtr><mtd><mi>a</mi></mtd><mtd><mi>b</mi></mtd></mtr>` +
+				`<mtr><mtd><mi>c</mi></mtd><mtd><mi>d</mi></mtd></mtr></mtable><mo fence="true">)</mo></mrow>`,
+		},
+		{
+			`\begin{cases} 1 & x>0 \\ 0 & \text{else} \end{cases}`,
+			`<mrow><mo fence="true">{</mo><mtable columnalign="left left"><mtr><mtd><mn>1</mn></mtd><mtd><mi>x</mi><mo>&gt;</mo><mn>0</mn></mtd></mtr>` +
+				`<mtr><mtd><mn>0</mn></mtd><mtd><mtext>else</mtext></mtd></mtr></mtable></mrow>`,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := LaTeXToMathML(tt.tex, false)
+		if err != nil {
+			t.Errorf("LaTeXToMathML(%q) failed: %v", tt.tex, err)
+			continue
+		}
+		if body := mathBody(t, got); body != tt.want {
+			t.Errorf("LaTeXToMathML(%q) = %s, want %s", tt.tex, body, tt.want)
+		}
+	}
+}
+
+func TestLaTeXToMathMLWrapper(t *testing.T) {
+	tests := []struct {
+		tex     string
+		display bool
+		want    string
+	}{
+		{
+			`x`, false,
+			`<math xmlns="http://www.w3.org/1998/Math/MathML"><semantics><mi>x</mi>` +
+				`<annotation encoding="application/x-tex">x</annotation></semantics></math>`,
+		},
+		{
+			`a < b`, true,
+			`<math xmlns="http://www.w3.org/1998/Math/MathML" display="block"><semantics><mrow><mi>a</mi><mo>&lt;</mo><mi>b</mi></mrow>` +
+				`<annotation encoding="application/x-tex">a &lt; b</annotation></semantics></math>`,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := LaTeXToMathML(tt.tex, tt.display)
+		if err != nil {
+			t.Errorf("LaTeXToMathML(%q, %v) failed: %v", tt.tex, tt.display, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("LaTeXToMathML(%q, %v) = %s, want %s", tt.tex, tt.display, got, tt.want)
+		}
+	}
+}
+
+func TestLaTeXToMathMLErrors(t *testing.T) {
+	tests := []struct {
+		tex  string
+		want string
+	}{
+		{`x^`, "missing argument"},
+		{`^2`, "script without base"},
+		{`x^2^3`, "double ^"},
+		{`x_1_2`, "double _"},
+		{`\frac{a}`, "missing argument"},
+		{`\foo`, `unsupported command \foo`},
+		{`{x`, `missing "}"`},
+		{`x}`, `unexpected "}"`},
+		{`a & b`, "unexpected &"},
+		{`\sqrt[3`, `missing "]"`},
+		{`\text{x`, `missing "}"`},
+		{`\left( x`, `missing "\\right"`},
+		{`\left< x \right>`, `invalid delimiter "<"`},
+		{`\begin{foo}x\end{foo}`, "unsupported environment foo"},
+		{`\begin{matrix}x\end{pmatrix}`, `\begin{matrix} ended by \end{pmatrix}`},
+		{strings.Repeat("{", maxMathDepth+1) + strings.Repeat("}", maxMathDepth+1), "formula nested too deeply"},
+		{strings.Repeat("x", maxMathLength+1), "formula longer than 4096 bytes"},
+	}
+
+	for _, tt := range tests {
+		_, err := LaTeXToMathML(tt.tex, false)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("LaTeXToMathML(%.40q) error = %v, want %q", tt.tex, err, tt.want)
+		}
+	}
+}
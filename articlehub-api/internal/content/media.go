@@ -0,0 +1,85 @@
+package content
+
+import (
+	"strconv"
+	"strings"
+
+	"articlehub-api/internal/model"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// mediaSizes tells browsers how wide article images are displayed, so they
+// pick the smallest variant that fills the column.
+const mediaSizes = "(max-width: 768px) 100vw, 768px"
+
+var mediaKey = parser.NewContextKey()
+
+// mediaTransformer points images whose destination is a media reference,
+// ![alt](media:ID), at the stored original and lists its variants in
+// srcset. An image without alt text takes the one saved in the library.
+// References missing from the media map are left alone; the sanitiser drops
+// their unknown scheme.
+type mediaTransformer struct{}
+
+func (mediaTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	library, _ := pc.Get(mediaKey).(map[string]model.Media)
+	if len(library) == 0 {
+		return
+	}
+
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		img, ok := node.(*ast.Image)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		id, ok := mediaID(string(img.Destination))
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+		media, ok := library[id]
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		img.Destination = []byte(media.URL)
+		img.SetAttributeString("width", []byte(strconv.Itoa(media.Width)))
+		img.SetAttributeString("height", []byte(strconv.Itoa(media.Height)))
+		if len(media.Variants) > 0 {
+			img.SetAttributeString("srcset", []byte(media.SrcSet()))
+			img.SetAttributeString("sizes", []byte(mediaSizes))
+		}
+		img.SetAttributeString("loading", []byte("lazy"))
+		img.SetAttributeString("decoding", []byte("async"))
+		if !img.HasChildren() && media.Alt != "" {
+			img.AppendChild(img, ast.NewString([]byte(media.Alt)))
+		}
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// MediaReferences returns the IDs of the media an article's Markdown refers
+// to, each once, in order of appearance.
+func (r *Renderer) MediaReferences(source string) []string {
+	doc := r.markdown.Parser().Parse(text.NewReader([]byte(source)))
+
+	var ids []string
+	seen := map[string]bool{}
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if img, ok := node.(*ast.Image); ok && entering {
+			if id, ok := mediaID(string(img.Destination)); ok && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return ids
+}
+
+func mediaID(destination string) (string, bool) {
+	id, ok := strings.CutPrefix(destination, model.MediaReference)
+	return strings.ToLower(id), ok && id != ""
+}
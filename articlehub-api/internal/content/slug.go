@@ -0,0 +1,73 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength bounds generated slugs, leaving room for a collision suffix.
+const MaxSlugLength = 80
+
+// Letters that do not decompose into a base letter and combining marks.
+var transliterations = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "Æ", "ae", "œ", "oe", "Œ", "oe", "ø", "o", "Ø", "o",
+	"đ", "d", "Đ", "d", "ł", "l", "Ł", "l", "þ", "th", "Þ", "th",
+)
+
+// Slugify turns a title or name into a lowercase, hyphen separated slug.
+// Accented Latin letters lose their accents ("Ação" becomes "acao"). Letters
+// and digits of scripts without an ASCII spelling are kept as they are
+// ("日本語" stays "日本語"), so such titles still get a slug of their own;
+// everything else separates words. The result is empty when nothing usable
+// is left.
+func Slugify(s string) string {
+	s = transliterations.Replace(norm.NFKD.String(s))
+
+	var b strings.Builder
+	pendingDash := false
+	latin := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Accents left over from decomposing a Latin letter are dropped,
+			// the marks other scripts are written with are kept.
+			if !latin {
+				b.WriteRune(r)
+			}
+		case 'a' <= r && r <= 'z' || '0' <= r && r <= '9':
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			latin = true
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mc, r):
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			latin = false
+			b.WriteRune(r)
+		default:
+			pendingDash = true
+			latin = false
+		}
+	}
+
+	// Scripts such as Hangul were decomposed above and are put back together.
+	slug := norm.NFC.String(b.String())
+	if len(slug) > MaxSlugLength {
+		cut := MaxSlugLength
+		for cut > 0 && !utf8.RuneStart(slug[cut]) {
+			cut--
+		}
+		slug = slug[:cut]
+		if i := strings.LastIndexByte(slug, '-'); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	return strings.TrimRight(slug, "-")
+}
@@ -0,0 +1,67 @@
+package content
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Hello,   World!  ", "hello-world"},
+		{"Top 10", "top-10"},
+		{"Report 2024", "report-2024"},
+		{"Ação e Reação", "acao-e-reacao"},
+		{"Crème brûlée", "creme-brulee"},
+		{"Straße", "strasse"},
+		{"Æsir Øl Łódź", "aesir-ol-lodz"},
+		{"Tiếng Việt", "tieng-viet"},
+		{"ﬁle №1", "file-no1"},
+		{"C++ & Go", "c-go"},
+		{"日本語", "日本語"},
+		{"日本語 の記事", "日本語-の記事"},
+		{"Go 入門", "go-入門"},
+		{"Привет, мир", "привет-мир"},
+		{"한국어", "한국어"},
+		{"हिन्दी", "हिन्दी"},
+		{"", ""},
+		{"!!!", ""},
+		{"---", ""},
+		{"🙂", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Slugify(tt.in); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSlugifyLength(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"short", "a b", "a-b"},
+		{"cut at word", strings.Repeat("word ", 30), strings.TrimSuffix(strings.Repeat("word-", 16), "-")},
+		{"single long word", strings.Repeat("a", MaxSlugLength+5), strings.Repeat("a", MaxSlugLength)},
+		{"multibyte", strings.Repeat("語", MaxSlugLength), strings.Repeat("語", MaxSlugLength/3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slugify(tt.in)
+			if got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if len(got) > MaxSlugLength || !utf8.ValidString(got) {
+				t.Errorf("Slugify(%q) = %q, want at most %d bytes of valid UTF-8", tt.in, got, MaxSlugLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package content
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"articlehub-api/internal/model"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Reading speeds used to estimate reading time. Scripts written without
+// spaces between words are read, and counted, character by character.
+const (
+	wordsPerMinute      = 230
+	charactersPerMinute = 500
+	codeLinesPerMinute  = 40
+)
+
+// Seconds spent on the first image; each following one takes a second less,
+// down to imageSecondsMin.
+const (
+	imageSecondsFirst = 12
+	imageSecondsMin   = 3
+)
+
+// analyze walks a parsed article and computes its word count, reading time
+// and table of contents. Code blocks are left out of the word count but
+// still take time to read, as do images.
+func analyze(doc ast.Node, src []byte) model.ArticleStats {
+	stats := model.ArticleStats{TOC: []model.TOCEntry{}}
+	var words, characters, codeLines, images int
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			id, _ := node.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			stats.TOC = append(stats.TOC, model.TOCEntry{
+				Level: node.Level,
+				ID:    string(idBytes),
+				Text:  plainText(node, src),
+			})
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			codeLines += n.Lines().Len()
+			return ast.WalkSkipChildren, nil
+		case *ast.Image:
+			images++
+			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			words++
+		case *ast.Text:
+			w, c := countWords(node.Segment.Value(src))
+			words += w
+			characters += c
+		}
+		return ast.WalkContinue, nil
+	})
+
+	stats.WordCount = words + characters
+	seconds := float64(words)*60/wordsPerMinute +
+		float64(characters)*60/charactersPerMinute +
+		float64(codeLines)*60/codeLinesPerMinute
+	for i := 0; i < images; i++ {
+		seconds += float64(max(imageSecondsFirst-i, imageSecondsMin))
+	}
+	if seconds > 0 {
+		stats.ReadingTime = max(1, int(math.Round(seconds/60)))
+	}
+	return stats
+}
+
+// countWords counts the space separated words of text, and separately the
+// characters of scripts that do not separate words with spaces.
+func countWords(text []byte) (words, characters int) {
+	inWord := false
+	for _, r := range string(text) {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai):
+			characters++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		case r == '\'' || r == '’' || r == '-':
+			// Part of words such as "d'água" or "guarda-chuva".
+		default:
+			inWord = false
+		}
+	}
+	return words, characters
+}
+
+// plainText returns the text of an inline node and its descendants.
+func plainText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := child.(type) {
+		case *ast.Text:
+			b.Write(node.Segment.Value(src))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
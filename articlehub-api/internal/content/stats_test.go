@@ -0,0 +1,120 @@
+package content
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"articlehub-api/internal/model"
+)
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		in         string
+		words      int
+		characters int
+	}{
+		{"", 0, 0},
+		{"   ", 0, 0},
+		{"one", 1, 0},
+		{"one two  three", 3, 0},
+		{"Hello, world!", 2, 0},
+		{"don't stop", 2, 0},
+		{"d’água guarda-chuva", 2, 0},
+		{"naïve café", 2, 0},
+		{"version 2.0", 3, 0},
+		{"日本語", 0, 3},
+		{"Go言語 入門", 1, 4},
+		{"ภาษาไทย", 0, 7},
+		{"Привет мир", 2, 0},
+		{"— … !", 0, 0},
+	}
+
+	for _, tt := range tests {
+		words, characters := countWords([]byte(tt.in))
+		if words != tt.words || characters != tt.characters {
+			t.Errorf("countWords(%q) = %d, %d, want %d, %d", tt.in, words, characters, tt.words, tt.characters)
+		}
+	}
+}
+
+func TestRenderArticleStats(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want model.ArticleStats
+	}{
+		{
+			name: "empty",
+			in:   "",
+			want: model.ArticleStats{TOC: []model.TOCEntry{}},
+		},
+		{
+			name: "short text",
+			in:   "Just a few words here.",
+			want: model.ArticleStats{WordCount: 5, ReadingTime: 1, TOC: []model.TOCEntry{}},
+		},
+		{
+			name: "reading speed",
+			in:   strings.Repeat("word ", 460),
+			want: model.ArticleStats{WordCount: 460, ReadingTime: 2, TOC: []model.TOCEntry{}},
+		},
+		{
+			name: "characters",
+			in:   strings.Repeat("語", 1500),
+			want: model.ArticleStats{WordCount: 1500, ReadingTime: 3, TOC: []model.TOCEntry{}},
+		},
+		{
+			name: "code is timed but not counted",
+			in:   "Intro\n\n```go\n" + strings.Repeat("x := 1\n", 80) + "```\n",
+			want: model.ArticleStats{WordCount: 1, ReadingTime: 2, TOC: []model.TOCEntry{}},
+		},
+		{
+			name: "images",
+			in:   strings.Repeat("![alt text](https://example.com/a.png)\n\n", 10),
+			want: model.ArticleStats{WordCount: 0, ReadingTime: 1, TOC: []model.TOCEntry{}},
+		},
+		{
+			name: "formatting and links",
+			in:   "Some **bold** and _emphasised_ text with a [link](https://example.com) and https://example.org.",
+			want: model.ArticleStats{WordCount: 10, ReadingTime: 1, TOC: []model.TOCEntry{}},
+		},
+		{
+			name: "table of contents",
+			in:   "# Title\n\nText\n\n## First *part*\n\n### Details\n\n## First part\n\n## 日本語\n",
+			want: model.ArticleStats{WordCount: 10, ReadingTime: 1, TOC: []model.TOCEntry{
+				{Level: 1, ID: "title", Text: "Title"},
+				{Level: 2, ID: "first-part", Text: "First part"},
+				{Level: 3, ID: "details", Text: "Details"},
+				{Level: 2, ID: "first-part-2", Text: "First part"},
+				{Level: 2, ID: "日本語", Text: "日本語"},
+			}},
+		},
+		{
+			name: "heading without words",
+			in:   "## !!!\n\n## !!!\n",
+			want: model.ArticleStats{WordCount: 0, ReadingTime: 0, TOC: []model.TOCEntry{
+				{Level: 2, ID: "section", Text: "!!!"},
+				{Level: 2, ID: "section-2", Text: "!!!"},
+			}},
+		},
+	}
+
+	r := NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, stats, err := r.RenderArticle(tt.in, nil)
+			if err != nil {
+				t.Fatalf("RenderArticle(%q) failed: %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(stats, tt.want) {
+				t.Errorf("RenderArticle(%q) stats = %+v, want %+v", tt.in, stats, tt.want)
+			}
+			for _, entry := range stats.TOC {
+				if !strings.Contains(html, `id="`+entry.ID+`"`) {
+					t.Errorf("RenderArticle(%q) has no anchor %q in %s", tt.in, entry.ID, html)
+				}
+			}
+		})
+	}
+}
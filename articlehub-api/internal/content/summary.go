@@ -0,0 +1,29 @@
+package content
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+var stripAll = bluemonday.StrictPolicy()
+
+// Summary returns the plain text of rendered article HTML, cut at a word
+// boundary so that it is at most maxRunes characters long, ellipsis included.
+func Summary(bodyHTML string, maxRunes int) string {
+	// Keep words of adjacent block elements apart once tags are removed.
+	text := html.UnescapeString(stripAll.Sanitize(strings.ReplaceAll(bodyHTML, "<", " <")))
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+
+	runes := []rune(text)[:maxRunes-1]
+	cut := string(runes)
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
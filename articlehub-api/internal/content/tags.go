@@ -0,0 +1,59 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxTagLength is the longest tag, in characters, kept after normalisation.
+const MaxTagLength = 50
+
+// NormalizeTag turns free-form tag input into its canonical spelling:
+// lowercase, without a leading '#', with runs of spaces and underscores
+// collapsed into a single '-'. Characters other than letters, digits and
+// "-+#." are dropped so that tags like "c++", "c#" and ".net" survive. It
+// returns an empty string when no letter or digit remains.
+func NormalizeTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(strings.ToLower(tag)), "#")
+
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range tag {
+		switch {
+		case unicode.IsSpace(r) || r == '_' || r == '-':
+			pendingDash = b.Len() > 0
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("+#.", r):
+			if pendingDash {
+				b.WriteRune('-')
+				pendingDash = false
+			}
+			b.WriteRune(r)
+		}
+	}
+
+	normalized := []rune(b.String())
+	if len(normalized) > MaxTagLength {
+		normalized = normalized[:MaxTagLength]
+	}
+	result := strings.TrimRight(string(normalized), "-")
+	if strings.IndexFunc(result, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+		return ""
+	}
+	return result
+}
+
+// NormalizeTags normalises every tag and drops empty values and duplicates,
+// keeping the first occurrence order.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
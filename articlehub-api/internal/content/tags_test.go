@@ -0,0 +1,67 @@
+package content
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Go", "go"},
+		{"  #Golang ", "golang"},
+		{"Machine Learning", "machine-learning"},
+		{"machine__learning", "machine-learning"},
+		{"web - dev", "web-dev"},
+		{"-leading and trailing-", "leading-and-trailing"},
+		{"C++", "c++"},
+		{"C#", "c#"},
+		{".NET", ".net"},
+		{"node.js", "node.js"},
+		{"Ünïcödé", "ünïcödé"},
+		{"日本語", "日本語"},
+		{"2024", "2024"},
+		{"hello, world!", "hello-world"},
+		{"", ""},
+		{"   ", ""},
+		{"#", ""},
+		{"...", ""},
+		{"+++", ""},
+		{"#.#", ""},
+		{"- _ -", ""},
+		{"!?", ""},
+		{strings.Repeat("a", MaxTagLength+10), strings.Repeat("a", MaxTagLength)},
+		{strings.Repeat("a", MaxTagLength-1) + " b", strings.Repeat("a", MaxTagLength-1)},
+		{strings.Repeat(".", MaxTagLength) + "net", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeTag(tt.in); got != tt.want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"nil", nil, []string{}},
+		{"duplicates", []string{"Go", "#go", " GO "}, []string{"go"}},
+		{"order kept", []string{"rust", "go", "rust"}, []string{"rust", "go"}},
+		{"unusable dropped", []string{"...", "", "go", "#"}, []string{"go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTags(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,19 @@ import (
 // Service represents a service that interacts with a database.
 type Service interface {
 	UserRepo() repository.UserRepository
+	ExportRepo() repository.ExportRepository
+	AuditRepo() repository.AuditRepository
+	ArticleRepo() repository.ArticleRepository
+	TagRepo() repository.TagRepository
+	CommentRepo() repository.CommentRepository
+	ReactionRepo() repository.ReactionRepository
+	FollowRepo() repository.FollowRepository
+	ReadingListRepo() repository.ReadingListRepository
+	SeriesRepo() repository.SeriesRepository
+	AnalyticsRepo() repository.AnalyticsRepository
+	TrendingRepo() repository.TrendingRepository
+	RelatedRepo() repository.RelatedRepository
+	MediaRepo() repository.MediaRepository
 
 	// Health returns a map of health status information.
 	// The keys and values in the map are service-specific.
@@ -23,15 +36,41 @@ type Service interface {
 }
 
 type service struct {
-	db       *sql.DB
-	userRepo repository.UserRepository
+	db              *sql.DB
+	userRepo        repository.UserRepository
+	exportRepo      repository.ExportRepository
+	auditRepo       repository.AuditRepository
+	articleRepo     repository.ArticleRepository
+	tagRepo         repository.TagRepository
+	commentRepo     repository.CommentRepository
+	reactionRepo    repository.ReactionRepository
+	followRepo      repository.FollowRepository
+	readingListRepo repository.ReadingListRepository
+	seriesRepo      repository.SeriesRepository
+	analyticsRepo   repository.AnalyticsRepository
+	trendingRepo    repository.TrendingRepository
+	relatedRepo     repository.RelatedRepository
+	mediaRepo       repository.MediaRepository
 }
 
 func New() Service {
 	db := NewConnection()
 	return &service{
-		db:       db,
-		userRepo: repository.NewUserRepository(db),
+		db:              db,
+		userRepo:        repository.NewUserRepository(db),
+		exportRepo:      repository.NewExportRepository(db),
+		auditRepo:       repository.NewAuditRepository(db),
+		articleRepo:     repository.NewArticleRepository(db),
+		tagRepo:         repository.NewTagRepository(db),
+		commentRepo:     repository.NewCommentRepository(db),
+		reactionRepo:    repository.NewReactionRepository(db),
+		followRepo:      repository.NewFollowRepository(db),
+		readingListRepo: repository.NewReadingListRepository(db),
+		seriesRepo:      repository.NewSeriesRepository(db),
+		analyticsRepo:   repository.NewAnalyticsRepository(db),
+		trendingRepo:    repository.NewTrendingRepository(db),
+		relatedRepo:     repository.NewRelatedRepository(db),
+		mediaRepo:       repository.NewMediaRepository(db),
 	}
 }
 
@@ -39,6 +78,58 @@ func (s *service) UserRepo() repository.UserRepository {
 	return s.userRepo
 }
 
+func (s *service) ExportRepo() repository.ExportRepository {
+	return s.exportRepo
+}
+
+func (s *service) AuditRepo() repository.AuditRepository {
+	return s.auditRepo
+}
+
+func (s *service) ArticleRepo() repository.ArticleRepository {
+	return s.articleRepo
+}
+
+func (s *service) TagRepo() repository.TagRepository {
+	return s.tagRepo
+}
+
+func (s *service) CommentRepo() repository.CommentRepository {
+	return s.commentRepo
+}
+
+func (s *service) ReactionRepo() repository.ReactionRepository {
+	return s.reactionRepo
+}
+
+func (s *service) FollowRepo() repository.FollowRepository {
+	return s.followRepo
+}
+
+func (s *service) ReadingListRepo() repository.ReadingListRepository {
+	return s.readingListRepo
+}
+
+func (s *service) SeriesRepo() repository.SeriesRepository {
+	return s.seriesRepo
+}
+
+func (s *service) AnalyticsRepo() repository.AnalyticsRepository {
+	return s.analyticsRepo
+}
+
+func (s *service) TrendingRepo() repository.TrendingRepository {
+	return s.trendingRepo
+}
+
+func (s *service) RelatedRepo() repository.RelatedRepository {
+	return s.relatedRepo
+}
+
+func (s *service) MediaRepo() repository.MediaRepository {
+	return s.mediaRepo
+}
+
 func (s *service) Health() map[string]string {
 	return Health(s.db)
 }
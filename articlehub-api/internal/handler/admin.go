@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"articlehub-api/internal/audit"
+	"articlehub-api/internal/auth"
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminHandler serves the administrator user-management endpoints.
+type AdminHandler struct {
+	Repo  repository.UserRepository
+	Audit *audit.Service
+	// ImpersonationTTL is how long an impersonation token stays valid.
+	ImpersonationTTL time.Duration
+}
+
+func NewAdminHandler(repo repository.UserRepository, auditService *audit.Service, impersonationTTL time.Duration) *AdminHandler {
+	return &AdminHandler{Repo: repo, Audit: auditService, ImpersonationTTL: impersonationTTL}
+}
+
+// ListUsers searches accounts by name or email (q), role and status
+// (active, suspended or deactivated), paginated with limit and offset.
+func (h *AdminHandler) ListUsers(c *fiber.Ctx) error {
+	filter := model.UserFilter{
+		Query:  c.Query("q"),
+		Role:   c.Query("role"),
+		Status: c.Query("status"),
+		Limit:  c.QueryInt("limit", 50),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if filter.Limit < 1 || filter.Limit > 500 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	users, err := h.Repo.SearchUsers(ctx, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve users",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"users": users,
+		"count": len(users),
+	})
+}
+
+func (h *AdminHandler) GetUser(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := h.Repo.GetUserAccount(ctx, c.Params("id"))
+	if err != nil {
+		return userLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"user": user,
+	})
+}
+
+func (h *AdminHandler) GetUserStats(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := h.Repo.GetUserStats(ctx, c.Params("id"))
+	if err != nil {
+		return userLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"stats": stats,
+	})
+}
+
+func (h *AdminHandler) ChangeRole(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req model.ChangeRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if !model.ValidRole(req.Role) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid role",
+		})
+	}
+	if id == middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot change your own role",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := h.Repo.GetUserAccount(ctx, id)
+	if err != nil {
+		return userLookupError(c, err)
+	}
+
+	if err := h.Repo.SetUserRole(ctx, id, req.Role); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to change role",
+		})
+	}
+
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionAdminRoleChange,
+		TargetType: "user",
+		TargetID:   id,
+		Changes: map[string]model.AuditChange{
+			"role": {From: user.Role, To: req.Role},
+		},
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role changed successfully",
+	})
+}
+
+func (h *AdminHandler) SuspendUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req model.SuspendUserRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+	if id == middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot suspend yourself",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.SuspendUser(ctx, id, req.Reason); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found or already suspended",
+		})
+	}
+
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionAdminSuspend,
+		TargetType: "user",
+		TargetID:   id,
+		Changes: map[string]model.AuditChange{
+			"suspension_reason": {To: req.Reason},
+		},
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "User suspended successfully",
+	})
+}
+
+func (h *AdminHandler) UnsuspendUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.UnsuspendUser(ctx, id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found or not suspended",
+		})
+	}
+
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionAdminUnsuspend,
+		TargetType: "user",
+		TargetID:   id,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "User unsuspended successfully",
+	})
+}
+
+// ForcePasswordReset signs the user out and requires a new password before
+// any other authenticated request is accepted.
+func (h *AdminHandler) ForcePasswordReset(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.RequirePasswordReset(ctx, id); err != nil {
+		return userLookupError(c, err)
+	}
+
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionAdminForcePasswordReset,
+		TargetType: "user",
+		TargetID:   id,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Password reset required on next login",
+	})
+}
+
+func (h *AdminHandler) RevokeSessions(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.RevokeSessions(ctx, id); err != nil {
+		return userLookupError(c, err)
+	}
+
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionAdminRevokeSessions,
+		TargetType: "user",
+		TargetID:   id,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "All sessions revoked",
+	})
+}
+
+// Impersonate issues a token that lets the calling administrator act as the
+// user. Responses to impersonated requests carry the X-Impersonated-By
+// header, sensitive operations are refused and every request is audited.
+func (h *AdminHandler) Impersonate(c *fiber.Ctx) error {
+	id := c.Params("id")
+	actorID := middleware.CurrentUserID(c)
+	if id == actorID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot impersonate yourself",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := h.Repo.GetUserAccount(ctx, id)
+	if err != nil {
+		return userLookupError(c, err)
+	}
+	if user.Role == model.RoleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Administrators cannot be impersonated",
+		})
+	}
+	if user.DeactivatedAt != nil || user.SuspendedAt != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Only active users can be impersonated",
+		})
+	}
+
+	actor, err := h.Repo.GetUserAuthState(ctx, actorID)
+	if err != nil {
+		return userLookupError(c, err)
+	}
+	subject, err := h.Repo.GetUserAuthState(ctx, id)
+	if err != nil {
+		return userLookupError(c, err)
+	}
+
+	token, expiresAt, err := auth.CreateImpersonationToken(actorID, actor.SessionGeneration, id,
+		subject.SessionGeneration, h.ImpersonationTTL)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate token",
+		})
+	}
+
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionAdminImpersonate,
+		TargetType: "user",
+		TargetID:   id,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":    "Impersonation token issued",
+		"token":      token,
+		"expires_at": expiresAt,
+	})
+}
+
+func userLookupError(c *fiber.Ctx, err error) error {
+	if err.Error() == "user not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve user",
+	})
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"articlehub-api/internal/analytics"
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultStatsRange is the number of days covered by /me/stats when the
+// caller gives no range.
+const defaultStatsRange = 30
+
+// AnalyticsHandler records article views and reports them to authors. No
+// personal data is stored: visitors are only known by a daily salted hash of
+// their IP address and user agent.
+type AnalyticsHandler struct {
+	Repo     repository.AnalyticsRepository
+	Articles repository.ArticleRepository
+	// SiteHost is the site's own host name, whose referrers are internal
+	// navigation rather than traffic sources.
+	SiteHost string
+	// MaxRange is the longest range of days one stats request may cover.
+	MaxRange int
+
+	mu      sync.Mutex
+	saltDay time.Time
+	salt    []byte
+}
+
+func NewAnalyticsHandler(repo repository.AnalyticsRepository, articles repository.ArticleRepository, siteURL string, maxRange int) *AnalyticsHandler {
+	h := &AnalyticsHandler{Repo: repo, Articles: articles, MaxRange: maxRange}
+	if u, err := url.Parse(siteURL); err == nil {
+		h.SiteHost = u.Hostname()
+	}
+	return h
+}
+
+// RecordView counts a view of an article. Clients send it once the article
+// is displayed; the response is the same whether or not the view counted.
+func (h *AnalyticsHandler) RecordView(c *fiber.Ctx) error {
+	return h.record(c, false)
+}
+
+// RecordRead marks the caller's view of an article as a read. Clients send it
+// once the reader has reached the end of the article or spent a good part of
+// its reading time on it.
+func (h *AnalyticsHandler) RecordRead(c *fiber.Ctx) error {
+	return h.record(c, true)
+}
+
+func (h *AnalyticsHandler) record(c *fiber.Ctx, read bool) error {
+	var req model.RecordVisitRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if analytics.IsBot(userAgent) {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := articleParam(ctx, c, h.Articles, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+
+	day := analytics.Day(time.Now())
+	salt, err := h.dailySalt(ctx, day)
+	if err != nil {
+		log.Printf("error retrieving analytics salt: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record view",
+		})
+	}
+
+	referrer := req.Referrer
+	if referrer == "" {
+		referrer = c.Get(fiber.HeaderReferer)
+	}
+	visit := &model.ArticleVisit{
+		ArticleID: id,
+		ViewerID:  middleware.CurrentUserID(c),
+		Day:       day,
+		Visitor:   analytics.VisitorID(salt, c.IP(), userAgent),
+		Referrer:  analytics.Referrer(referrer, h.SiteHost),
+		Read:      read,
+	}
+	if err := h.Repo.RecordVisit(ctx, visit); err != nil {
+		log.Printf("error recording view of article %s: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to record view",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// dailySalt returns the salt of day, only asking the database when the day
+// changes.
+func (h *AnalyticsHandler) dailySalt(ctx context.Context, day time.Time) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.salt != nil && h.saltDay.Equal(day) {
+		return h.salt, nil
+	}
+	salt, err := h.Repo.DailySalt(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+	h.saltDay, h.salt = day, salt
+	return salt, nil
+}
+
+// GetStats returns the caller's audience between from and to, two dates in
+// YYYY-MM-DD form, both included. The range defaults to the last 30 days.
+func (h *AnalyticsHandler) GetStats(c *fiber.Ctx) error {
+	to := analytics.Day(time.Now())
+	from := to.AddDate(0, 0, 1-defaultStatsRange)
+	for param, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.DateOnly, v)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid " + param + " date, use YYYY-MM-DD",
+				})
+			}
+			*dst = t
+		}
+	}
+	if from.After(to) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "from must not be after to",
+		})
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > h.MaxRange {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Date range is too long",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := h.Repo.GetAuthorStats(ctx, middleware.CurrentUserID(c), from, to)
+	if err != nil {
+		log.Printf("error retrieving author stats: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve stats",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"stats": stats,
+	})
+}
@@ -0,0 +1,691 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/content"
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+	"articlehub-api/internal/seo"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ArticleHandler struct {
+	Repo     repository.ArticleRepository
+	Series   repository.SeriesRepository
+	Media    repository.MediaRepository
+	Renderer *content.Renderer
+	Site     seo.Site
+}
+
+func NewArticleHandler(repo repository.ArticleRepository, series repository.SeriesRepository, media repository.MediaRepository,
+	renderer *content.Renderer, site seo.Site) *ArticleHandler {
+	return &ArticleHandler{Repo: repo, Series: series, Media: media, Renderer: renderer, Site: site}
+}
+
+func (h *ArticleHandler) CreateArticle(c *fiber.Ctx) error {
+	var req model.CreateArticleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Title == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Title is required",
+		})
+	}
+	if req.Body == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Body is required",
+		})
+	}
+
+	tags := content.NormalizeTags(req.Tags)
+	if len(tags) > model.MaxArticleTags {
+		return tooManyTags(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	authorID := middleware.CurrentUserID(c)
+	media, missing, err := h.bodyMedia(ctx, authorID, req.Body)
+	if err != nil {
+		log.Printf("error resolving article media: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create article",
+		})
+	}
+	if len(missing) > 0 {
+		return unknownMedia(c, missing)
+	}
+	var cover *model.Media
+	if req.CoverMediaID != "" {
+		if cover, err = h.coverMedia(ctx, authorID, req.CoverMediaID); err != nil {
+			return coverMediaError(c, err)
+		}
+	}
+
+	bodyHTML, stats, err := h.Renderer.RenderArticle(req.Body, media)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": "Failed to render article body",
+		})
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate article ID",
+		})
+	}
+
+	article := &model.Article{
+		ID:           id.String(),
+		AuthorID:     authorID,
+		Title:        req.Title,
+		Body:         req.Body,
+		BodyHTML:     bodyHTML,
+		Status:       model.ArticleStatusDraft,
+		Tags:         tags,
+		CategoryIDs:  req.CategoryIDs,
+		Reactions:    map[string]int{},
+		Cover:        cover,
+		ArticleStats: stats,
+	}
+	if article.CategoryIDs == nil {
+		article.CategoryIDs = []string{}
+	}
+
+	if err := h.Repo.CreateArticle(ctx, article); err != nil {
+		if err.Error() == "category not found" {
+			return unknownCategory(c)
+		}
+		log.Printf("error creating article: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create article",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Article created successfully",
+		"article": article,
+	})
+}
+
+func (h *ArticleHandler) GetArticles(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := model.ArticleFilter{
+		ViewerID:   middleware.CurrentUserID(c),
+		AuthorID:   c.Query("author"),
+		AnyTag:     c.Query("match") == "any",
+		CategoryID: c.Query("category"),
+	}
+	var tags []string
+	for _, value := range c.Context().QueryArgs().PeekMulti("tag") {
+		tags = append(tags, strings.Split(string(value), ",")...)
+	}
+	filter.Tags = content.NormalizeTags(tags)
+
+	if filter.AuthorID != "" && uuid.Validate(filter.AuthorID) != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid author ID",
+		})
+	}
+	if filter.CategoryID != "" && uuid.Validate(filter.CategoryID) != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	articles, err := h.Repo.GetArticles(ctx, filter)
+	if err != nil {
+		log.Printf("error listing articles: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve articles",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"articles": articles,
+		"count":    len(articles),
+	})
+}
+
+func (h *ArticleHandler) GetArticleById(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := articleParam(ctx, c, h.Repo, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Repo.GetVisibleArticle(ctx, id, middleware.CurrentUserID(c))
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if redirectToSlug(c, "id", article.Slug) {
+		return nil
+	}
+
+	article.Series, err = h.Series.GetNavigation(ctx, article.ID, middleware.CurrentUserID(c))
+	if err != nil {
+		log.Printf("error loading series navigation: %v", err)
+	}
+	article.SEO = h.Site.Article(article)
+
+	// Reactions, series navigation and the cover change the response without
+	// bumping the article's version, so the ETag covers the whole body.
+	body, err := json.Marshal(fiber.Map{
+		"article": article,
+		"message": "Article retrieved successfully",
+	})
+	if err != nil {
+		log.Printf("error encoding article: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve article",
+		})
+	}
+	if notModified(c, representationETag(article.Version, body)) {
+		return nil
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+func (h *ArticleHandler) UpdateArticle(c *fiber.Ctx) error {
+	var req model.UpdateArticleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := articleParam(ctx, c, h.Repo, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Repo.GetArticleById(ctx, id)
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if article.AuthorID != middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only edit your own articles",
+		})
+	}
+	if preconditionFailed(c, article.Version) {
+		return nil
+	}
+
+	if req.Title != "" {
+		article.Title = req.Title
+	}
+	if req.Body != "" {
+		media, missing, err := h.bodyMedia(ctx, article.AuthorID, req.Body)
+		if err != nil {
+			log.Printf("error resolving article media: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update article",
+			})
+		}
+		if len(missing) > 0 {
+			return unknownMedia(c, missing)
+		}
+		bodyHTML, stats, err := h.Renderer.RenderArticle(req.Body, media)
+		if err != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": "Failed to render article body",
+			})
+		}
+		article.Body = req.Body
+		article.BodyHTML = bodyHTML
+		article.ArticleStats = stats
+	}
+	if req.Tags != nil {
+		article.Tags = content.NormalizeTags(req.Tags)
+		if len(article.Tags) > model.MaxArticleTags {
+			return tooManyTags(c)
+		}
+	}
+	if req.CategoryIDs != nil {
+		article.CategoryIDs = req.CategoryIDs
+	}
+	if req.CoverMediaID != nil {
+		article.Cover = nil
+		if *req.CoverMediaID != "" {
+			if article.Cover, err = h.coverMedia(ctx, article.AuthorID, *req.CoverMediaID); err != nil {
+				return coverMediaError(c, err)
+			}
+		}
+	}
+
+	if err := h.Repo.UpdateArticle(ctx, id, article, middleware.CurrentUserID(c)); err != nil {
+		if err.Error() == "version mismatch" {
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error": "Resource was modified since it was retrieved",
+			})
+		}
+		if err.Error() == "category not found" {
+			return unknownCategory(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update article",
+		})
+	}
+	c.Set(fiber.HeaderETag, etag(article.Version))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Article updated successfully",
+		"article": article,
+	})
+}
+
+func (h *ArticleHandler) DeleteArticle(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := articleParam(ctx, c, h.Repo, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Repo.GetArticleById(ctx, id)
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if article.AuthorID != middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only delete your own articles",
+		})
+	}
+
+	if err := h.Repo.DeleteArticle(ctx, id); err != nil {
+		return articleLookupError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Article deleted successfully",
+	})
+}
+
+// TransitionArticle moves an article through the publishing workflow. Only
+// the author, or an administrator reviewing it, may change its status.
+func (h *ArticleHandler) TransitionArticle(c *fiber.Ctx) error {
+	var req model.TransitionArticleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := articleParam(ctx, c, h.Repo, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Repo.GetArticleById(ctx, id)
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if !canManageArticle(c, article) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only change the status of your own articles",
+		})
+	}
+
+	if !model.CanTransition(article.Status, req.Status, middleware.CurrentUserRole(c)) {
+		if model.CanTransition(article.Status, req.Status, model.RoleAdmin) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Only an administrator can approve an article for publication",
+			})
+		}
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Cannot move article from " + article.Status + " to " + req.Status,
+		})
+	}
+
+	var publishAt *time.Time
+	if req.Status == model.ArticleStatusScheduled {
+		if req.PublishAt == nil || !req.PublishAt.After(time.Now()) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Scheduling requires a publish_at date in the future",
+			})
+		}
+		publishAt = req.PublishAt
+	}
+
+	if err := h.Repo.TransitionArticle(ctx, article, req.Status, publishAt, middleware.CurrentUserID(c)); err != nil {
+		if err.Error() == "article status changed" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Article status was changed concurrently, please retry",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to change article status",
+		})
+	}
+
+	c.Set(fiber.HeaderETag, etag(article.Version))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Article status changed successfully",
+		"article": article,
+	})
+}
+
+func (h *ArticleHandler) GetTransitions(c *fiber.Ctx) error {
+	article, err := h.managedArticle(c)
+	if article == nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	transitions, err := h.Repo.GetTransitions(ctx, article.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve article history",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"transitions": transitions,
+		"count":       len(transitions),
+	})
+}
+
+func (h *ArticleHandler) GetRevisions(c *fiber.Ctx) error {
+	article, err := h.managedArticle(c)
+	if article == nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revisions, err := h.Repo.GetRevisions(ctx, article.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve revisions",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"revisions": revisions,
+		"count":     len(revisions),
+	})
+}
+
+func (h *ArticleHandler) GetRevision(c *fiber.Ctx) error {
+	article, err := h.managedArticle(c)
+	if article == nil {
+		return err
+	}
+
+	number, err := c.ParamsInt("number")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid revision number",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revision, err := h.Repo.GetRevision(ctx, article.ID, number)
+	if err != nil {
+		return revisionLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"revision": revision,
+	})
+}
+
+// DiffRevisions compares the bodies of revisions from and to, line by line
+// or, with mode=word, word by word.
+func (h *ArticleHandler) DiffRevisions(c *fiber.Ctx) error {
+	article, err := h.managedArticle(c)
+	if article == nil {
+		return err
+	}
+
+	from, to := c.QueryInt("from"), c.QueryInt("to")
+	if from < 1 || to < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "from and to revision numbers are required",
+		})
+	}
+	mode := c.Query("mode", content.DiffModeLine)
+	if mode != content.DiffModeLine && mode != content.DiffModeWord {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "mode must be line or word",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fromRev, err := h.Repo.GetRevision(ctx, article.ID, from)
+	if err != nil {
+		return revisionLookupError(c, err)
+	}
+	toRev, err := h.Repo.GetRevision(ctx, article.ID, to)
+	if err != nil {
+		return revisionLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"from":  from,
+		"to":    to,
+		"mode":  mode,
+		"title": content.Diff(fromRev.Title, toRev.Title, content.DiffModeWord),
+		"body":  content.Diff(fromRev.Body, toRev.Body, mode),
+	})
+}
+
+// RestoreRevision brings back the content of an older revision by saving it
+// as a new revision, so the history itself is never rewritten.
+func (h *ArticleHandler) RestoreRevision(c *fiber.Ctx) error {
+	article, err := h.managedArticle(c)
+	if article == nil {
+		return err
+	}
+	if article.AuthorID != middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only edit your own articles",
+		})
+	}
+
+	number, err := c.ParamsInt("number")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid revision number",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revision, err := h.Repo.GetRevision(ctx, article.ID, number)
+	if err != nil {
+		return revisionLookupError(c, err)
+	}
+
+	// Media deleted since the revision was saved is left out.
+	media, _, err := h.bodyMedia(ctx, article.AuthorID, revision.Body)
+	if err != nil {
+		log.Printf("error resolving article media: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to restore revision",
+		})
+	}
+	bodyHTML, stats, err := h.Renderer.RenderArticle(revision.Body, media)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": "Failed to render article body",
+		})
+	}
+	article.BodyHTML = bodyHTML
+	article.ArticleStats = stats
+
+	restored, err := h.Repo.RestoreRevision(ctx, article, revision, middleware.CurrentUserID(c))
+	if err != nil {
+		if err.Error() == "version mismatch" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Article was modified concurrently, please retry",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to restore revision",
+		})
+	}
+	restored.Body = ""
+	c.Set(fiber.HeaderETag, etag(article.Version))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":  "Revision restored successfully",
+		"article":  article,
+		"revision": restored,
+	})
+}
+
+// managedArticle loads the article named in the route and ensures the caller
+// may manage it. When it returns a nil article the error response has
+// already been written.
+func (h *ArticleHandler) managedArticle(c *fiber.Ctx) (*model.Article, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := articleParam(ctx, c, h.Repo, "id")
+	if err != nil {
+		return nil, articleLookupError(c, err)
+	}
+	article, err := h.Repo.GetArticleById(ctx, id)
+	if err != nil {
+		return nil, articleLookupError(c, err)
+	}
+	if !canManageArticle(c, article) {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only view the history of your own articles",
+		})
+	}
+	return article, nil
+}
+
+// canManageArticle reports whether the caller is the article's author or an
+// administrator.
+func canManageArticle(c *fiber.Ctx, article *model.Article) bool {
+	return article.AuthorID == middleware.CurrentUserID(c) || middleware.CurrentUserRole(c) == model.RoleAdmin
+}
+
+func tooManyTags(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": fmt.Sprintf("An article can have at most %d tags", model.MaxArticleTags),
+	})
+}
+
+// bodyMedia loads the media an article body refers to from its author's
+// library, along with the IDs of those it refers to but the author does not
+// own.
+func (h *ArticleHandler) bodyMedia(ctx context.Context, authorID, body string) (map[string]model.Media, []string, error) {
+	ids := h.Renderer.MediaReferences(body)
+	var valid, missing []string
+	for _, id := range ids {
+		if isID(id) {
+			valid = append(valid, id)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	media, err := h.Media.GetMediaByIDs(ctx, authorID, valid)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, id := range valid {
+		if _, ok := media[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return media, missing, nil
+}
+
+// coverMedia returns the media item id from the author's library.
+func (h *ArticleHandler) coverMedia(ctx context.Context, authorID, id string) (*model.Media, error) {
+	if !isID(id) {
+		return nil, fmt.Errorf("media not found")
+	}
+	media, err := h.Media.GetMediaByIDs(ctx, authorID, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	cover, ok := media[strings.ToLower(id)]
+	if !ok {
+		return nil, fmt.Errorf("media not found")
+	}
+	return &cover, nil
+}
+
+func unknownMedia(c *fiber.Ctx, ids []string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": "Media not found in your library: " + strings.Join(ids, ", "),
+	})
+}
+
+func coverMediaError(c *fiber.Ctx, err error) error {
+	if err.Error() == "media not found" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Cover media not found in your library",
+		})
+	}
+	log.Printf("error retrieving cover media: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve cover media",
+	})
+}
+
+func unknownCategory(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": "Category not found",
+	})
+}
+
+func revisionLookupError(c *fiber.Ctx, err error) error {
+	if err.Error() == "revision not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Revision not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve revision",
+	})
+}
+
+func articleLookupError(c *fiber.Ctx, err error) error {
+	if err.Error() == "article not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Article not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve article",
+	})
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	Repo repository.AuditRepository
+}
+
+func NewAuditHandler(repo repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{Repo: repo}
+}
+
+// ListEntries returns audit log entries, newest first. Supported filters are
+// actor_id, subject_id, action, target_type, target_id, ip, from and to (RFC 3339),
+// paginated with limit and offset.
+func (h *AuditHandler) ListEntries(c *fiber.Ctx) error {
+	filter := model.AuditFilter{
+		ActorID:    c.Query("actor_id"),
+		SubjectID:  c.Query("subject_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		IP:         c.Query("ip"),
+		Limit:      c.QueryInt("limit", 50),
+		Offset:     c.QueryInt("offset", 0),
+	}
+
+	for param, id := range map[string]string{"actor_id": filter.ActorID, "subject_id": filter.SubjectID} {
+		if id == "" {
+			continue
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid " + param,
+			})
+		}
+	}
+	for param, dst := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid " + param + " date, use RFC 3339",
+				})
+			}
+			*dst = t
+		}
+	}
+	if filter.Limit < 1 || filter.Limit > 500 {
+		filter.Limit = 50
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entries, err := h.Repo.ListEntries(ctx, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve audit log",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/content"
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// maxCommentLength is the longest comment body accepted, in characters.
+const maxCommentLength = 10000
+
+type CommentHandler struct {
+	Repo     repository.CommentRepository
+	Articles repository.ArticleRepository
+	Renderer *content.Renderer
+	// MaxDepth is how deeply replies may nest; top-level comments have
+	// depth 0.
+	MaxDepth int
+	// ThreadReplies is how many replies of each thread ListComments
+	// returns; the others are fetched with ListReplies.
+	ThreadReplies int
+}
+
+func NewCommentHandler(repo repository.CommentRepository, articles repository.ArticleRepository, renderer *content.Renderer, maxDepth, threadReplies int) *CommentHandler {
+	return &CommentHandler{Repo: repo, Articles: articles, Renderer: renderer, MaxDepth: maxDepth, ThreadReplies: threadReplies}
+}
+
+// ListComments returns a page of threads, each top-level comment carrying
+// its oldest nested replies, at most ThreadReplies of them. A comment whose
+// reply_count exceeds the replies it carries has more, available from
+// ListReplies. Pass next_cursor back as cursor to get the next page.
+func (h *CommentHandler) ListComments(c *fiber.Ctx) error {
+	cursor := c.Query("cursor")
+	if cursor != "" && uuid.Validate(cursor) != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid cursor",
+		})
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := articleParam(ctx, c, h.Articles, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Articles.GetVisibleArticle(ctx, id, middleware.CurrentUserID(c))
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+
+	comments, next, err := h.Repo.ListThreads(ctx, article.ID, cursor, limit, h.ThreadReplies, h.MaxDepth)
+	if err != nil {
+		log.Printf("error listing comments: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve comments",
+		})
+	}
+	threads := model.CommentTree(comments)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"comments":    threads,
+		"count":       len(threads),
+		"next_cursor": next,
+	})
+}
+
+// ListReplies returns a page of the direct replies of a comment, oldest
+// first. Each reply carries its reply_count, so deeper replies are fetched
+// the same way. Pass next_cursor back as cursor to get the next page.
+func (h *CommentHandler) ListReplies(c *fiber.Ctx) error {
+	cursor := c.Query("cursor")
+	if cursor != "" && uuid.Validate(cursor) != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid cursor",
+		})
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	articleID, err := articleParam(ctx, c, h.Articles, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if _, err := h.Articles.GetVisibleArticle(ctx, articleID, middleware.CurrentUserID(c)); err != nil {
+		return articleLookupError(c, err)
+	}
+	// Deleted comments stay in place as their replies' parents.
+	parent, err := h.Repo.GetComment(ctx, c.Params("commentId"))
+	if err != nil {
+		return commentLookupError(c, err)
+	}
+	if parent.ArticleID != articleID {
+		return commentLookupError(c, fmt.Errorf("comment not found"))
+	}
+
+	replies, next, err := h.Repo.ListReplies(ctx, parent.ID, cursor, limit)
+	if err != nil {
+		log.Printf("error listing replies: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve comments",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"replies":     replies,
+		"count":       len(replies),
+		"next_cursor": next,
+	})
+}
+
+func (h *CommentHandler) CreateComment(c *fiber.Ctx) error {
+	var req model.CreateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	articleID, err := articleParam(ctx, c, h.Articles, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Articles.GetVisibleArticle(ctx, articleID, middleware.CurrentUserID(c))
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if article.Status != model.ArticleStatusPublished {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Comments are only open on published articles",
+		})
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate comment ID",
+		})
+	}
+	comment := &model.Comment{
+		ID:        id.String(),
+		ArticleID: article.ID,
+		AuthorID:  middleware.CurrentUserID(c),
+		RootID:    id.String(),
+		Reactions: map[string]int{},
+	}
+
+	if req.ParentID != nil {
+		parent, err := h.Repo.GetComment(ctx, *req.ParentID)
+		if err != nil || parent.ArticleID != article.ID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Parent comment not found",
+			})
+		}
+		if parent.Deleted {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Cannot reply to a deleted comment",
+			})
+		}
+		if parent.Depth+1 > h.MaxDepth {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Replies cannot be nested more than %d levels deep", h.MaxDepth),
+			})
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = parent.RootID
+		comment.Depth = parent.Depth + 1
+	}
+
+	if h.invalidBody(c, comment, req.Body) {
+		return nil
+	}
+
+	if err := h.Repo.CreateComment(ctx, comment); err != nil {
+		log.Printf("error creating comment: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create comment",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Comment created successfully",
+		"comment": comment,
+	})
+}
+
+func (h *CommentHandler) UpdateComment(c *fiber.Ctx) error {
+	var req model.UpdateCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	comment, err := h.articleComment(ctx, c)
+	if comment == nil {
+		return err
+	}
+	if comment.AuthorID != middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only edit your own comments",
+		})
+	}
+
+	if h.invalidBody(c, comment, req.Body) {
+		return nil
+	}
+
+	if err := h.Repo.UpdateComment(ctx, comment); err != nil {
+		return commentLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Comment updated successfully",
+		"comment": comment,
+	})
+}
+
+// DeleteComment leaves a placeholder in the thread. Authors may delete their
+// own comments, administrators any of them.
+func (h *CommentHandler) DeleteComment(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	comment, err := h.articleComment(ctx, c)
+	if comment == nil {
+		return err
+	}
+	if comment.AuthorID != middleware.CurrentUserID(c) && middleware.CurrentUserRole(c) != model.RoleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only delete your own comments",
+		})
+	}
+
+	if err := h.Repo.DeleteComment(ctx, comment.ID); err != nil {
+		return commentLookupError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Comment deleted successfully",
+	})
+}
+
+// articleComment loads the comment named in the route, making sure it belongs
+// to the article in the route and has not been deleted. When it returns a nil
+// comment the error response has already been written.
+func (h *CommentHandler) articleComment(ctx context.Context, c *fiber.Ctx) (*model.Comment, error) {
+	articleID, err := articleParam(ctx, c, h.Articles, "id")
+	if err != nil {
+		return nil, articleLookupError(c, err)
+	}
+	comment, err := h.Repo.GetComment(ctx, c.Params("commentId"))
+	if err != nil {
+		return nil, commentLookupError(c, err)
+	}
+	if comment.ArticleID != articleID || comment.Deleted {
+		return nil, commentLookupError(c, fmt.Errorf("comment not found"))
+	}
+	return comment, nil
+}
+
+// invalidBody validates body and renders it into the comment. It reports
+// whether the body was rejected, in which case the error response has
+// already been written.
+func (h *CommentHandler) invalidBody(c *fiber.Ctx, comment *model.Comment, body string) bool {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Body is required",
+		})
+		return true
+	}
+	if len([]rune(body)) > maxCommentLength {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("Body must be at most %d characters", maxCommentLength),
+		})
+		return true
+	}
+
+	bodyHTML, err := h.Renderer.Render(body)
+	if err != nil || strings.TrimSpace(bodyHTML) == "" {
+		c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": "Failed to render comment body",
+		})
+		return true
+	}
+	comment.Body = body
+	comment.BodyHTML = bodyHTML
+	return false
+}
+
+func commentLookupError(c *fiber.Ctx, err error) error {
+	if err.Error() == "comment not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Comment not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve comment",
+	})
+}
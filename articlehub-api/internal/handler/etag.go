@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// etag returns the entity tag of a resource at the given version.
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// representationETag returns the entity tag of a response body that carries
+// more than the resource itself, such as reaction counts or series
+// navigation, which change without bumping its version. The tag is the
+// version followed by a hash of the body, so If-Match on writes still only
+// compares versions.
+func representationETag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// notModified sets the ETag header and reports whether the client's
+// If-None-Match already matches it, in which case a 304 has been written.
+func notModified(c *fiber.Ctx, tag string) bool {
+	c.Set(fiber.HeaderETag, tag)
+	if matchesAny(c.Get(fiber.HeaderIfNoneMatch), tag, true) {
+		c.Status(fiber.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// preconditionFailed enforces If-Match on a write to a resource currently at
+// version. It reports whether the request must be rejected, in which case the
+// 428 or 412 response has already been written.
+func preconditionFailed(c *fiber.Ctx, version int) bool {
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" {
+		c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{
+			"error": "If-Match header is required, use the ETag from the last GET",
+		})
+		return true
+	}
+	if !matchesVersion(ifMatch, version) {
+		c.Set(fiber.HeaderETag, etag(version))
+		c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": "Resource was modified since it was retrieved",
+		})
+		return true
+	}
+	return false
+}
+
+// matchesVersion reports whether an If-Match header lists a tag for version,
+// either a plain version tag or one made by representationETag.
+func matchesVersion(header string, version int) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		tag, ok := strings.CutPrefix(candidate, `"`)
+		if !ok {
+			continue
+		}
+		tag, ok = strings.CutSuffix(tag, `"`)
+		if !ok {
+			continue
+		}
+		tag, _, _ = strings.Cut(tag, "-")
+		if tag == strconv.Itoa(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether tag appears in the comma separated list of
+// entity tags from an If-Match or If-None-Match header. If-None-Match uses
+// weak comparison, which ignores the W/ prefix.
+func matchesAny(header, tag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
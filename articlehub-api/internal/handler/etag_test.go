@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"articlehub-api/internal/model"
+)
+
+func TestRepresentationETag(t *testing.T) {
+	base := model.Article{ID: "a", Title: "Title", Version: 3, Reactions: map[string]int{"like": 1}}
+	tag := func(article model.Article) string {
+		body, err := json.Marshal(map[string]any{"article": article})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return representationETag(article.Version, body)
+	}
+
+	reacted := base
+	reacted.Reactions = map[string]int{"like": 2}
+	inSeries := base
+	inSeries.Series = &model.SeriesNavigation{}
+	covered := base
+	covered.Cover = &model.Media{ID: "m"}
+
+	tests := []struct {
+		name    string
+		article model.Article
+		changed bool
+	}{
+		{"same body", base, false},
+		{"reaction added", reacted, true},
+		{"joined a series", inSeries, true},
+		{"cover set", covered, true},
+	}
+
+	want := tag(base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tag(tt.article)
+			if (got != want) != tt.changed {
+				t.Errorf("ETag %s, base %s, want changed %v", got, want, tt.changed)
+			}
+			if !strings.HasPrefix(got, `"3-`) {
+				t.Errorf("ETag %s does not start with the version", got)
+			}
+		})
+	}
+}
+
+func TestMatchesVersion(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"3"`, true},
+		{`"3-0123456789abcdef"`, true},
+		{`"2-0123456789abcdef", "3-fedcba9876543210"`, true},
+		{`*`, true},
+		{`"2"`, false},
+		{`"30-0123456789abcdef"`, false},
+		{`W/"3"`, false},
+		{`3`, false},
+		{``, false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesVersion(tt.header, 3); got != tt.want {
+			t.Errorf("matchesVersion(%q, 3) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"articlehub-api/internal/audit"
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ExportHandler struct {
+	Repo  repository.ExportRepository
+	Audit *audit.Service
+}
+
+func NewExportHandler(repo repository.ExportRepository, auditService *audit.Service) *ExportHandler {
+	return &ExportHandler{Repo: repo, Audit: auditService}
+}
+
+// CreateExport queues an export of all data held about the caller. The
+// archive is built in the background; poll GetExport until it is ready.
+func (h *ExportHandler) CreateExport(c *fiber.Ctx) error {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate export ID",
+		})
+	}
+
+	export := &model.DataExport{
+		ID:     id.String(),
+		UserID: middleware.CurrentUserID(c),
+		Status: model.ExportStatusPending,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.CreateExport(ctx, export); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create export",
+		})
+	}
+
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionExportCreate,
+		TargetType: "export",
+		TargetID:   export.ID,
+	})
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Export requested",
+		"export":  export,
+	})
+}
+
+func (h *ExportHandler) GetExport(c *fiber.Ctx) error {
+	export, err := h.ownExport(c)
+	if export == nil {
+		return err
+	}
+
+	resp := fiber.Map{
+		"export": export,
+	}
+	if export.Status == model.ExportStatusReady {
+		resp["download_url"] = fmt.Sprintf("/users/me/exports/%s/download", export.ID)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+func (h *ExportHandler) DownloadExport(c *fiber.Ctx) error {
+	export, err := h.ownExport(c)
+	if export == nil {
+		return err
+	}
+
+	if export.Status != model.ExportStatusReady {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Export is not ready yet",
+		})
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		return c.Status(fiber.StatusGone).JSON(fiber.Map{
+			"error": "Export has expired, request a new one",
+		})
+	}
+
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionExportDownload,
+		TargetType: "export",
+		TargetID:   export.ID,
+	})
+
+	return c.Download(export.FilePath, fmt.Sprintf("articlehub-export-%s.zip", export.ID))
+}
+
+// ownExport loads the export named in the route and ensures it belongs to
+// the caller. When it returns a nil export the error response has already
+// been written.
+func (h *ExportHandler) ownExport(c *fiber.Ctx) (*model.DataExport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	export, err := h.Repo.GetExport(ctx, c.Params("id"))
+	if err != nil || export.UserID != middleware.CurrentUserID(c) {
+		if err == nil || err.Error() == "export not found" {
+			return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Export not found",
+			})
+		}
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve export",
+		})
+	}
+
+	return export, nil
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/content"
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FollowHandler struct {
+	Repo  repository.FollowRepository
+	Users repository.UserRepository
+}
+
+func NewFollowHandler(repo repository.FollowRepository, users repository.UserRepository) *FollowHandler {
+	return &FollowHandler{Repo: repo, Users: users}
+}
+
+// FollowUser makes the caller follow the user in the route. Following someone
+// twice is not an error.
+func (h *FollowHandler) FollowUser(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := userParam(ctx, c, h.Users, "id")
+	if err != nil {
+		return followLookupError(c, err)
+	}
+	if id == middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "You cannot follow yourself",
+		})
+	}
+
+	if _, err := h.Users.GetUserById(ctx, id); err != nil {
+		return followLookupError(c, err)
+	}
+	if err := h.Repo.FollowUser(ctx, middleware.CurrentUserID(c), id); err != nil {
+		return followLookupError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "User followed successfully",
+	})
+}
+
+func (h *FollowHandler) UnfollowUser(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := userParam(ctx, c, h.Users, "id")
+	if err != nil {
+		return followLookupError(c, err)
+	}
+	if err := h.Repo.UnfollowUser(ctx, middleware.CurrentUserID(c), id); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to unfollow user",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "User unfollowed successfully",
+	})
+}
+
+func (h *FollowHandler) ListFollowers(c *fiber.Ctx) error {
+	return h.listFollows(c, h.Repo.ListFollowers, "followers")
+}
+
+func (h *FollowHandler) ListFollowing(c *fiber.Ctx) error {
+	return h.listFollows(c, h.Repo.ListFollowing, "following")
+}
+
+// listFollows serves one of the follow lists of the user in the route,
+// paginated with limit and offset, along with both totals.
+func (h *FollowHandler) listFollows(c *fiber.Ctx, list func(context.Context, string, int, int) ([]model.FollowUser, error), key string) error {
+	limit := c.QueryInt("limit", 50)
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := userParam(ctx, c, h.Users, "id")
+	if err != nil {
+		return followLookupError(c, err)
+	}
+	user, err := h.Users.GetUserById(ctx, id)
+	if err != nil {
+		return followLookupError(c, err)
+	}
+
+	users, err := list(ctx, user.ID, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve " + key,
+		})
+	}
+	counts, err := h.Repo.GetFollowCounts(ctx, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve " + key,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		key:      users,
+		"count":  len(users),
+		"counts": counts,
+	})
+}
+
+func (h *FollowHandler) FollowTag(c *fiber.Ctx) error {
+	tag := content.NormalizeTag(c.Params("tag"))
+	if tag == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid tag",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	canonical, err := h.Repo.FollowTag(ctx, middleware.CurrentUserID(c), tag)
+	if err != nil {
+		log.Printf("error following tag: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to follow tag",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Tag followed successfully",
+		"tag":     canonical,
+	})
+}
+
+func (h *FollowHandler) UnfollowTag(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.UnfollowTag(ctx, middleware.CurrentUserID(c), content.NormalizeTag(c.Params("tag"))); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to unfollow tag",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Tag unfollowed successfully",
+	})
+}
+
+func (h *FollowHandler) ListFollowedTags(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tags, err := h.Repo.ListFollowedTags(ctx, middleware.CurrentUserID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve followed tags",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tags":  tags,
+		"count": len(tags),
+	})
+}
+
+// GetFeed returns the latest published articles from the authors and tags
+// the caller follows. Pass next_cursor back as cursor to get the next page.
+func (h *FollowHandler) GetFeed(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	articles, next, err := h.Repo.GetFeed(ctx, middleware.CurrentUserID(c), c.Query("cursor"), limit)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid cursor",
+			})
+		}
+		log.Printf("error building feed: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve feed",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"articles":    articles,
+		"count":       len(articles),
+		"next_cursor": next,
+	})
+}
+
+func followLookupError(c *fiber.Ctx, err error) error {
+	if err.Error() == "user not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve user",
+	})
+}
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"articlehub-api/internal/images"
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+	"articlehub-api/internal/storage"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// uploadTimeout bounds an upload, which stores the original and each of its
+// variants one after the other.
+const uploadTimeout = time.Minute
+
+// MediaHandler manages the images users upload for article covers and
+// bodies.
+type MediaHandler struct {
+	Repo    repository.MediaRepository
+	Storage storage.Storage
+	// Widths are the widths, in pixels, responsive variants are made at.
+	Widths []int
+	// MaxSize is the largest file, in bytes, that can be uploaded.
+	MaxSize int64
+	Decoder *images.Decoder
+}
+
+func NewMediaHandler(repo repository.MediaRepository, store storage.Storage, widths []int, maxSize int64,
+	decoder *images.Decoder) *MediaHandler {
+	return &MediaHandler{Repo: repo, Storage: store, Widths: widths, MaxSize: maxSize, Decoder: decoder}
+}
+
+// UploadMedia adds the image in the multipart field "file" to the caller's
+// library, with the optional alt text in the field "alt". The image is
+// decoded to be scaled, so it is read whole, though never past MaxSize.
+func (h *MediaHandler) UploadMedia(c *fiber.Ctx) error {
+	var data []byte
+	values, err := streamImage(c, "file", h.MaxSize, func(file io.Reader, _ images.Format) error {
+		var err error
+		data, err = io.ReadAll(file)
+		return err
+	})
+	if err != nil {
+		return uploadError(c, err, h.MaxSize)
+	}
+
+	alt := strings.TrimSpace(values["alt"])
+	if utf8.RuneCountInString(alt) > model.MaxMediaAltLength {
+		return altTooLong(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+
+	img, release, err := h.Decoder.Decode(ctx, data)
+	if err != nil {
+		return imageError(c, err)
+	}
+	defer release()
+	original, err := img.Original()
+	if err != nil {
+		return imageError(c, err)
+	}
+	variants, err := img.Variants(h.Widths)
+	if err != nil {
+		log.Printf("error scaling image: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to process image",
+		})
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate media ID",
+		})
+	}
+	media := &model.Media{
+		ID:          id.String(),
+		OwnerID:     middleware.CurrentUserID(c),
+		ContentType: img.ContentType,
+		Width:       img.Width,
+		Height:      img.Height,
+		Size:        int64(len(original)),
+		Alt:         alt,
+		Variants:    []model.MediaVariant{},
+	}
+	prefix := fmt.Sprintf("media/%s/%s/", media.OwnerID, media.ID)
+
+	key := prefix + "original" + img.Ext
+	if err := h.Storage.Put(ctx, key, img.ContentType, bytes.NewReader(original)); err != nil {
+		return storageError(c, err)
+	}
+	media.URL = h.Storage.URL(key)
+	media.Keys = append(media.Keys, key)
+
+	for _, variant := range variants {
+		key := prefix + strconv.Itoa(variant.Width) + "w" + variant.Ext
+		if err := h.Storage.Put(ctx, key, variant.ContentType, bytes.NewReader(variant.Data)); err != nil {
+			h.discard(media.Keys)
+			return storageError(c, err)
+		}
+		media.Keys = append(media.Keys, key)
+		media.Variants = append(media.Variants, model.MediaVariant{
+			Width:  variant.Width,
+			Height: variant.Height,
+			URL:    h.Storage.URL(key),
+		})
+	}
+
+	if err := h.Repo.CreateMedia(ctx, media); err != nil {
+		log.Printf("error saving media: %v", err)
+		h.discard(media.Keys)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save media",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Media uploaded successfully",
+		"media":   media,
+	})
+}
+
+// ListMedia returns the caller's library, newest first.
+func (h *MediaHandler) ListMedia(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 50)
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	library, err := h.Repo.ListMedia(ctx, middleware.CurrentUserID(c), limit, offset)
+	if err != nil {
+		log.Printf("error listing media: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve media",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"media": library,
+		"count": len(library),
+	})
+}
+
+func (h *MediaHandler) GetMedia(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	media, err := h.ownMedia(ctx, c)
+	if err != nil {
+		return mediaLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"media": media,
+	})
+}
+
+// UpdateMedia changes the alt text of a media item. Articles pick it up the
+// next time they are saved.
+func (h *MediaHandler) UpdateMedia(c *fiber.Ctx) error {
+	var req model.UpdateMediaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	req.Alt = strings.TrimSpace(req.Alt)
+	if utf8.RuneCountInString(req.Alt) > model.MaxMediaAltLength {
+		return altTooLong(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	media, err := h.ownMedia(ctx, c)
+	if err != nil {
+		return mediaLookupError(c, err)
+	}
+	media.Alt = req.Alt
+	if err := h.Repo.UpdateMediaAlt(ctx, media); err != nil {
+		return mediaLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Media updated successfully",
+		"media":   media,
+	})
+}
+
+// DeleteMedia removes a media item and its stored files, unless an article
+// still uses it.
+func (h *MediaHandler) DeleteMedia(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	media, err := h.ownMedia(ctx, c)
+	if err != nil {
+		return mediaLookupError(c, err)
+	}
+	if err := h.Repo.DeleteMedia(ctx, media.ID); err != nil {
+		if err.Error() == "media in use" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Media is used by an article",
+			})
+		}
+		return mediaLookupError(c, err)
+	}
+	h.discard(media.Keys)
+
+	return c.JSON(fiber.Map{
+		"message": "Media deleted successfully",
+	})
+}
+
+// ownMedia loads the media item named by the id parameter. Other users'
+// media is reported as not found.
+func (h *MediaHandler) ownMedia(ctx context.Context, c *fiber.Ctx) (*model.Media, error) {
+	id := c.Params("id")
+	if !isID(id) {
+		return nil, fmt.Errorf("media not found")
+	}
+	media, err := h.Repo.GetMedia(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if media.OwnerID != middleware.CurrentUserID(c) {
+		return nil, fmt.Errorf("media not found")
+	}
+	return media, nil
+}
+
+// discard deletes stored objects that no media row refers to. Failures are
+// only logged: the objects are orphaned, not exposed.
+func (h *MediaHandler) discard(keys []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.Storage.Delete(ctx, keys...); err != nil {
+		log.Printf("error deleting stored media %v: %v", keys, err)
+	}
+}
+
+func mediaLookupError(c *fiber.Ctx, err error) error {
+	if err.Error() == "media not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Media not found",
+		})
+	}
+	log.Printf("error retrieving media: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve media",
+	})
+}
+
+func altTooLong(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": fmt.Sprintf("Alt text can have at most %d characters", model.MaxMediaAltLength),
+	})
+}
+
+func imageError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, images.ErrUnsupported):
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "Only JPEG, PNG, GIF and WebP images are supported",
+		})
+	case errors.Is(err, images.ErrTooLarge):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": fmt.Sprintf("Images can have at most %d pixels", images.MaxPixels),
+		})
+	case errors.Is(err, context.DeadlineExceeded):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Too many images are being processed, try again later",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to process image",
+	})
+}
+
+// storageError reports a failed upload to the object store.
+func storageError(c *fiber.Ctx, err error) error {
+	log.Printf("error uploading to storage: %v", err)
+	return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+		"error": "Failed to upload file to storage",
+	})
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReactionHandler serves reactions to articles and to their comments. The
+// same handlers are mounted on both, the target being the comment when the
+// route names one.
+type ReactionHandler struct {
+	Repo     repository.ReactionRepository
+	Articles repository.ArticleRepository
+	Comments repository.CommentRepository
+}
+
+func NewReactionHandler(repo repository.ReactionRepository, articles repository.ArticleRepository, comments repository.CommentRepository) *ReactionHandler {
+	return &ReactionHandler{Repo: repo, Articles: articles, Comments: comments}
+}
+
+// ListReactionTypes returns the reactions readers can use and their emoji.
+func (h *ReactionHandler) ListReactionTypes(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"reactions": model.Reactions,
+	})
+}
+
+func (h *ReactionHandler) GetReactions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	target, targetID := h.reactionTarget(ctx, c, false)
+	if target == "" {
+		return nil
+	}
+
+	summary, err := h.Repo.GetReactions(ctx, target, targetID, middleware.CurrentUserID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve reactions",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
+
+// SetReaction makes the reaction in the route the caller's reaction,
+// replacing any previous one. Repeating the request has no further effect.
+func (h *ReactionHandler) SetReaction(c *fiber.Ctx) error {
+	reaction := c.Params("reaction")
+	if !model.ValidReaction(reaction) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Unknown reaction",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	target, targetID := h.reactionTarget(ctx, c, true)
+	if target == "" {
+		return nil
+	}
+
+	summary, err := h.Repo.SetReaction(ctx, target, targetID, middleware.CurrentUserID(c), reaction)
+	if err != nil {
+		log.Printf("error saving reaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save reaction",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
+
+// RemoveReaction withdraws the caller's reaction. It succeeds even when there
+// was none.
+func (h *ReactionHandler) RemoveReaction(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	target, targetID := h.reactionTarget(ctx, c, true)
+	if target == "" {
+		return nil
+	}
+
+	summary, err := h.Repo.RemoveReaction(ctx, target, targetID, middleware.CurrentUserID(c))
+	if err != nil {
+		log.Printf("error removing reaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to remove reaction",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
+
+// reactionTarget resolves the article, or the comment when the route names
+// one, that the request reacts to. Reacting requires a published article and
+// a comment that was not deleted. When it returns an empty target the error
+// response has already been written.
+func (h *ReactionHandler) reactionTarget(ctx context.Context, c *fiber.Ctx, write bool) (string, string) {
+	articleID, err := articleParam(ctx, c, h.Articles, "id")
+	if err != nil {
+		articleLookupError(c, err)
+		return "", ""
+	}
+	article, err := h.Articles.GetVisibleArticle(ctx, articleID, middleware.CurrentUserID(c))
+	if err != nil {
+		articleLookupError(c, err)
+		return "", ""
+	}
+	if write && article.Status != model.ArticleStatusPublished {
+		c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Only published articles can be reacted to",
+		})
+		return "", ""
+	}
+
+	commentID := c.Params("commentId")
+	if commentID == "" {
+		return model.ReactionTargetArticle, article.ID
+	}
+
+	comment, err := h.Comments.GetComment(ctx, commentID)
+	if err != nil {
+		commentLookupError(c, err)
+		return "", ""
+	}
+	if comment.ArticleID != article.ID || (write && comment.Deleted) {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Comment not found",
+		})
+		return "", ""
+	}
+	return model.ReactionTargetComment, comment.ID
+}
@@ -0,0 +1,467 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ReadingListHandler serves the caller's bookmarks and reading lists, and
+// the lists other users chose to share.
+type ReadingListHandler struct {
+	Repo     repository.ReadingListRepository
+	Articles repository.ArticleRepository
+	Users    repository.UserRepository
+}
+
+func NewReadingListHandler(repo repository.ReadingListRepository, articles repository.ArticleRepository, users repository.UserRepository) *ReadingListHandler {
+	return &ReadingListHandler{Repo: repo, Articles: articles, Users: users}
+}
+
+func (h *ReadingListHandler) ListBookmarks(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 50)
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bookmarks, err := h.Repo.ListBookmarks(ctx, middleware.CurrentUserID(c), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve bookmarks",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"bookmarks": bookmarks,
+		"count":     len(bookmarks),
+	})
+}
+
+// SaveBookmark bookmarks the article in the route, or updates the note of an
+// existing bookmark.
+func (h *ReadingListHandler) SaveBookmark(c *fiber.Ctx) error {
+	var req model.BookmarkRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+	if invalidNote(c, req.Note) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	articleID, err := articleParam(ctx, c, h.Articles, "articleId")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Articles.GetVisibleArticle(ctx, articleID, middleware.CurrentUserID(c))
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+
+	bookmark, err := h.Repo.SaveBookmark(ctx, middleware.CurrentUserID(c), article.ID, strings.TrimSpace(req.Note))
+	if err != nil {
+		log.Printf("error saving bookmark: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to save bookmark",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":  "Bookmark saved successfully",
+		"bookmark": bookmark,
+	})
+}
+
+func (h *ReadingListHandler) DeleteBookmark(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	articleID, err := articleParam(ctx, c, h.Articles, "articleId")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if err := h.Repo.DeleteBookmark(ctx, middleware.CurrentUserID(c), articleID); err != nil {
+		if err.Error() == "bookmark not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Bookmark not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete bookmark",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Bookmark deleted successfully",
+	})
+}
+
+// ListReadingLists returns the caller's own reading lists.
+func (h *ReadingListHandler) ListReadingLists(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return h.listReadingLists(ctx, c, middleware.CurrentUserID(c), false)
+}
+
+// ListPublicReadingLists returns the public reading lists of the user in the
+// route.
+func (h *ReadingListHandler) ListPublicReadingLists(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ownerID, err := userParam(ctx, c, h.Users, "id")
+	if err != nil {
+		return userLookupError(c, err)
+	}
+	return h.listReadingLists(ctx, c, ownerID, true)
+}
+
+func (h *ReadingListHandler) listReadingLists(ctx context.Context, c *fiber.Ctx, ownerID string, publicOnly bool) error {
+	lists, err := h.Repo.ListReadingLists(ctx, ownerID, publicOnly)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve reading lists",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"lists": lists,
+		"count": len(lists),
+	})
+}
+
+func (h *ReadingListHandler) CreateReadingList(c *fiber.Ctx) error {
+	var req model.ReadingListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if msg := validateReadingList(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate reading list ID",
+		})
+	}
+	token, err := shareToken()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate share token",
+		})
+	}
+
+	list := &model.ReadingList{
+		ID:          id.String(),
+		OwnerID:     middleware.CurrentUserID(c),
+		Name:        req.Name,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+		ShareToken:  token,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.CreateReadingList(ctx, list); err != nil {
+		log.Printf("error creating reading list: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create reading list",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Reading list created successfully",
+		"list":    list,
+	})
+}
+
+// GetReadingList returns one of the caller's lists with its items.
+func (h *ReadingListHandler) GetReadingList(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.ownedList(ctx, c)
+	if list == nil {
+		return err
+	}
+	return h.withItems(ctx, c, list)
+}
+
+// GetSharedReadingList returns an unlisted or public list through its share
+// token, showing only the articles the viewer may see.
+func (h *ReadingListHandler) GetSharedReadingList(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.Repo.GetReadingListByToken(ctx, c.Params("token"))
+	if err != nil {
+		return readingListLookupError(c, err)
+	}
+	return h.withItems(ctx, c, list)
+}
+
+func (h *ReadingListHandler) withItems(ctx context.Context, c *fiber.Ctx, list *model.ReadingList) error {
+	items, err := h.Repo.ListItems(ctx, list.ID, middleware.CurrentUserID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve reading list",
+		})
+	}
+	list.Items = items
+	list.ItemCount = len(items)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"list": list,
+	})
+}
+
+func (h *ReadingListHandler) UpdateReadingList(c *fiber.Ctx) error {
+	var req model.ReadingListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if msg := validateReadingList(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.ownedList(ctx, c)
+	if list == nil {
+		return err
+	}
+
+	list.Name = req.Name
+	list.Description = req.Description
+	list.Visibility = req.Visibility
+	if err := h.Repo.UpdateReadingList(ctx, list); err != nil {
+		return readingListLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Reading list updated successfully",
+		"list":    list,
+	})
+}
+
+func (h *ReadingListHandler) DeleteReadingList(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.ownedList(ctx, c)
+	if list == nil {
+		return err
+	}
+	if err := h.Repo.DeleteReadingList(ctx, list.ID); err != nil {
+		return readingListLookupError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reading list deleted successfully",
+	})
+}
+
+// SaveItem adds the article in the route to the end of the list, or updates
+// its note when it is already there.
+func (h *ReadingListHandler) SaveItem(c *fiber.Ctx) error {
+	var req model.ReadingListItemRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+	if invalidNote(c, req.Note) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.ownedList(ctx, c)
+	if list == nil {
+		return err
+	}
+	articleID, err := articleParam(ctx, c, h.Articles, "articleId")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Articles.GetVisibleArticle(ctx, articleID, middleware.CurrentUserID(c))
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+
+	item, err := h.Repo.SaveItem(ctx, list.ID, article.ID, strings.TrimSpace(req.Note))
+	if err != nil {
+		return readingListLookupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Reading list item saved successfully",
+		"item":    item,
+	})
+}
+
+func (h *ReadingListHandler) RemoveItem(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.ownedList(ctx, c)
+	if list == nil {
+		return err
+	}
+	articleID, err := articleParam(ctx, c, h.Articles, "articleId")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if err := h.Repo.RemoveItem(ctx, list.ID, articleID); err != nil {
+		return readingListLookupError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reading list item removed successfully",
+	})
+}
+
+// ReorderItems sets the order of the list from the article IDs in the body,
+// which must name every item of the list once.
+func (h *ReadingListHandler) ReorderItems(c *fiber.Ctx) error {
+	var req model.ReorderReadingListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.ownedList(ctx, c)
+	if list == nil {
+		return err
+	}
+	if err := h.Repo.ReorderItems(ctx, list.ID, req.ArticleIDs); err != nil {
+		if err.Error() == "items mismatch" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "article_ids must list every item of the reading list exactly once",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to reorder reading list",
+		})
+	}
+
+	return h.withItems(ctx, c, list)
+}
+
+// ownedList loads the list named in the route, which must belong to the
+// caller. Lists of other users are reported as not found so that private
+// lists do not leak. When it returns a nil list the error response has
+// already been written.
+func (h *ReadingListHandler) ownedList(ctx context.Context, c *fiber.Ctx) (*model.ReadingList, error) {
+	list, err := h.Repo.GetReadingList(ctx, c.Params("id"))
+	if err != nil {
+		return nil, readingListLookupError(c, err)
+	}
+	if list.OwnerID != middleware.CurrentUserID(c) {
+		return nil, readingListLookupError(c, fmt.Errorf("reading list not found"))
+	}
+	return list, nil
+}
+
+// validateReadingList trims the request, defaults the visibility to private
+// and returns a message describing the first problem found, or an empty
+// string.
+func validateReadingList(req *model.ReadingListRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Visibility == "" {
+		req.Visibility = model.ListVisibilityPrivate
+	}
+	switch {
+	case req.Name == "":
+		return "Name is required"
+	case len([]rune(req.Name)) > 100:
+		return "Name must be at most 100 characters"
+	case len([]rune(req.Description)) > 1000:
+		return "Description must be at most 1000 characters"
+	case !model.ValidListVisibility(req.Visibility):
+		return "Visibility must be private, unlisted or public"
+	}
+	return ""
+}
+
+// invalidNote reports whether a bookmark or item note is too long, in which
+// case the 400 response has already been written.
+func invalidNote(c *fiber.Ctx, note string) bool {
+	if len([]rune(note)) > 1000 {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Note must be at most 1000 characters",
+		})
+		return true
+	}
+	return false
+}
+
+// shareToken returns a random, URL-safe token for a list's shareable URL.
+func shareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func readingListLookupError(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "reading list not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Reading list not found",
+		})
+	case "item not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Article is not in this reading list",
+		})
+	}
+	log.Printf("error accessing reading list: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve reading list",
+	})
+}
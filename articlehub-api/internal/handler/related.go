@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RelatedHandler struct {
+	Repo     repository.RelatedRepository
+	Articles repository.ArticleRepository
+}
+
+func NewRelatedHandler(repo repository.RelatedRepository, articles repository.ArticleRepository) *RelatedHandler {
+	return &RelatedHandler{Repo: repo, Articles: articles}
+}
+
+// GetRelated returns the articles recommended as next reads for an article
+// the caller can see, best first. limit defaults to 5.
+func (h *RelatedHandler) GetRelated(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	id, err := articleParam(ctx, c, h.Articles, "id")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if _, err := h.Articles.GetVisibleArticle(ctx, id, middleware.CurrentUserID(c)); err != nil {
+		return articleLookupError(c, err)
+	}
+
+	limit := c.QueryInt("limit", 5)
+	if limit < 1 || limit > 20 {
+		limit = 5
+	}
+
+	related, err := h.Repo.GetRelated(ctx, id, limit)
+	if err != nil {
+		log.Printf("error retrieving related articles: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve related articles",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"articles": related,
+		"count":    len(related),
+	})
+}
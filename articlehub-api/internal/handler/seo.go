@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"time"
+
+	"articlehub-api/internal/repository"
+	"articlehub-api/internal/seo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SEOHandler struct {
+	Articles repository.ArticleRepository
+	Site     seo.Site
+	// Indexing false asks every crawler to stay away, e.g. on staging.
+	Indexing bool
+	Disallow []string
+	MaxAge   time.Duration
+}
+
+func NewSEOHandler(articles repository.ArticleRepository, site seo.Site, indexing bool, disallow []string,
+	maxAge time.Duration) *SEOHandler {
+	return &SEOHandler{Articles: articles, Site: site, Indexing: indexing, Disallow: disallow, MaxAge: maxAge}
+}
+
+func (h *SEOHandler) Robots(c *fiber.Ctx) error {
+	body := []byte(h.Site.Robots(h.Indexing, h.Disallow))
+	if h.cacheable(c, body) {
+		return nil
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.Send(body)
+}
+
+// Sitemap lists every published article. Once there are more than fit in a
+// single sitemap it serves a sitemap index of the pages instead.
+func (h *SEOHandler) Sitemap(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pages, err := h.Articles.GetSitemapPages(ctx, seo.MaxSitemapURLs)
+	if err != nil {
+		return sitemapError(c, err)
+	}
+	if len(pages) <= 1 {
+		return h.servePage(ctx, c, 1)
+	}
+
+	var sitemaps []seo.URL
+	for _, page := range pages {
+		sitemaps = append(sitemaps, seo.URL{Loc: h.Site.PageURL(page.Number), LastMod: page.LastModified})
+	}
+	body, err := seo.Index(sitemaps)
+	if err != nil {
+		return sitemapError(c, err)
+	}
+	if h.cacheable(c, body) {
+		return nil
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// SitemapPage serves one page of a sitemap split by Sitemap.
+func (h *SEOHandler) SitemapPage(c *fiber.Ctx) error {
+	number, err := strconv.Atoi(c.Params("page"))
+	if err != nil || number < 1 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Sitemap not found",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pages, err := h.Articles.GetSitemapPages(ctx, seo.MaxSitemapURLs)
+	if err != nil {
+		return sitemapError(c, err)
+	}
+	if number > len(pages) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Sitemap not found",
+		})
+	}
+	return h.servePage(ctx, c, number)
+}
+
+func (h *SEOHandler) servePage(ctx context.Context, c *fiber.Ctx, number int) error {
+	entries, err := h.Articles.GetSitemapEntries(ctx, number, seo.MaxSitemapURLs)
+	if err != nil {
+		return sitemapError(c, err)
+	}
+	urls := make([]seo.URL, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, seo.URL{Loc: h.Site.ArticleURL(entry.Slug), LastMod: entry.UpdatedAt})
+	}
+	body, err := seo.URLSet(urls)
+	if err != nil {
+		return sitemapError(c, err)
+	}
+	if h.cacheable(c, body) {
+		return nil
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// cacheable sets the caching headers of a crawler resource and reports
+// whether If-None-Match made a 304 response sufficient. The ETag hashes the
+// body rather than relying on the articles' update times, which do not move
+// when an article is unpublished or deleted.
+func (h *SEOHandler) cacheable(c *fiber.Ctx, body []byte) bool {
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(int(h.MaxAge.Seconds())))
+	sum := sha256.Sum256(body)
+	return notModified(c, `W/"`+hex.EncodeToString(sum[:16])+`"`)
+}
+
+func sitemapError(c *fiber.Ctx, err error) error {
+	log.Printf("error building sitemap: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to build sitemap",
+	})
+}
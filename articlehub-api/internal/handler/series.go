@@ -0,0 +1,334 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/middleware"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type SeriesHandler struct {
+	Repo     repository.SeriesRepository
+	Articles repository.ArticleRepository
+}
+
+func NewSeriesHandler(repo repository.SeriesRepository, articles repository.ArticleRepository) *SeriesHandler {
+	return &SeriesHandler{Repo: repo, Articles: articles}
+}
+
+// ListSeries returns the latest series, only those of one author when the
+// author query parameter is set.
+func (h *SeriesHandler) ListSeries(c *fiber.Ctx) error {
+	authorID := c.Query("author")
+	if authorID != "" && uuid.Validate(authorID) != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid author ID",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	series, err := h.Repo.ListSeries(ctx, authorID, middleware.CurrentUserID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve series",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"series": series,
+		"count":  len(series),
+	})
+}
+
+// GetSeries is the landing page of a series: its details and the parts the
+// caller can read, in order.
+func (h *SeriesHandler) GetSeries(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	series, err := h.Repo.GetSeries(ctx, c.Params("id"))
+	if err != nil {
+		return seriesLookupError(c, err)
+	}
+	return h.withParts(ctx, c, series)
+}
+
+func (h *SeriesHandler) withParts(ctx context.Context, c *fiber.Ctx, series *model.Series) error {
+	parts, err := h.Repo.ListParts(ctx, series.ID, middleware.CurrentUserID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve series",
+		})
+	}
+	series.Parts = parts
+	series.PartCount = len(parts)
+	c.Set(fiber.HeaderETag, etag(series.Version))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"series": series,
+	})
+}
+
+func (h *SeriesHandler) CreateSeries(c *fiber.Ctx) error {
+	var req model.SeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if msg := validateSeries(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate series ID",
+		})
+	}
+	series := &model.Series{
+		ID:          id.String(),
+		AuthorID:    middleware.CurrentUserID(c),
+		Title:       req.Title,
+		Description: req.Description,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.CreateSeries(ctx, series); err != nil {
+		log.Printf("error creating series: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create series",
+		})
+	}
+	c.Set(fiber.HeaderETag, etag(series.Version))
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Series created successfully",
+		"series":  series,
+	})
+}
+
+func (h *SeriesHandler) UpdateSeries(c *fiber.Ctx) error {
+	var req model.SeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if msg := validateSeries(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	series, err := h.ownedSeries(ctx, c)
+	if series == nil {
+		return err
+	}
+	if preconditionFailed(c, series.Version) {
+		return nil
+	}
+
+	series.Title = req.Title
+	series.Description = req.Description
+	if err := h.Repo.UpdateSeries(ctx, series); err != nil {
+		return seriesWriteError(c, err)
+	}
+	c.Set(fiber.HeaderETag, etag(series.Version))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Series updated successfully",
+		"series":  series,
+	})
+}
+
+// DeleteSeries removes the series but keeps its articles.
+func (h *SeriesHandler) DeleteSeries(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	series, err := h.ownedSeries(ctx, c)
+	if series == nil {
+		return err
+	}
+	if err := h.Repo.DeleteSeries(ctx, series.ID); err != nil {
+		return seriesLookupError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Series deleted successfully",
+	})
+}
+
+// AddArticle appends one of the caller's articles to the end of the series.
+func (h *SeriesHandler) AddArticle(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	series, err := h.ownedSeries(ctx, c)
+	if series == nil || h.staleSeries(c, series) {
+		return err
+	}
+	articleID, err := articleParam(ctx, c, h.Articles, "articleId")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	article, err := h.Articles.GetArticleById(ctx, articleID)
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if article.AuthorID != series.AuthorID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only your own articles can be added to your series",
+		})
+	}
+
+	if err := h.Repo.AddArticle(ctx, series, article.ID); err != nil {
+		return seriesWriteError(c, err)
+	}
+	return h.withParts(ctx, c, series)
+}
+
+func (h *SeriesHandler) RemoveArticle(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	series, err := h.ownedSeries(ctx, c)
+	if series == nil || h.staleSeries(c, series) {
+		return err
+	}
+	articleID, err := articleParam(ctx, c, h.Articles, "articleId")
+	if err != nil {
+		return articleLookupError(c, err)
+	}
+	if err := h.Repo.RemoveArticle(ctx, series, articleID); err != nil {
+		return seriesWriteError(c, err)
+	}
+	return h.withParts(ctx, c, series)
+}
+
+// ReorderArticles sets the order of the series from the article IDs in the
+// body, which must name every part once. It requires If-Match so that an
+// order computed from an outdated view of the series is rejected.
+func (h *SeriesHandler) ReorderArticles(c *fiber.Ctx) error {
+	var req model.ReorderSeriesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	series, err := h.ownedSeries(ctx, c)
+	if series == nil {
+		return err
+	}
+	if preconditionFailed(c, series.Version) {
+		return nil
+	}
+
+	if err := h.Repo.ReorderArticles(ctx, series, req.ArticleIDs); err != nil {
+		return seriesWriteError(c, err)
+	}
+	return h.withParts(ctx, c, series)
+}
+
+// ownedSeries loads the series named in the route and ensures it belongs to
+// the caller. When it returns a nil series the error response has already
+// been written.
+func (h *SeriesHandler) ownedSeries(ctx context.Context, c *fiber.Ctx) (*model.Series, error) {
+	series, err := h.Repo.GetSeries(ctx, c.Params("id"))
+	if err != nil {
+		return nil, seriesLookupError(c, err)
+	}
+	if series.AuthorID != middleware.CurrentUserID(c) {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only edit your own series",
+		})
+	}
+	return series, nil
+}
+
+// staleSeries applies an optional If-Match to adding or removing a part. It
+// reports whether the request must be rejected, in which case the 412
+// response has already been written. Without If-Match the change applies to
+// the series as it is when the lock is taken.
+func (h *SeriesHandler) staleSeries(c *fiber.Ctx, series *model.Series) bool {
+	if c.Get(fiber.HeaderIfMatch) == "" {
+		series.Version = 0
+		return false
+	}
+	return preconditionFailed(c, series.Version)
+}
+
+// validateSeries trims the request and returns a message describing the
+// first problem found, or an empty string.
+func validateSeries(req *model.SeriesRequest) string {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Description = strings.TrimSpace(req.Description)
+	switch {
+	case req.Title == "":
+		return "Title is required"
+	case len([]rune(req.Title)) > 200:
+		return "Title must be at most 200 characters"
+	case len([]rune(req.Description)) > 2000:
+		return "Description must be at most 2000 characters"
+	}
+	return ""
+}
+
+func seriesLookupError(c *fiber.Ctx, err error) error {
+	if err.Error() == "series not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Series not found",
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to retrieve series",
+	})
+}
+
+func seriesWriteError(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "series not found":
+		return seriesLookupError(c, err)
+	case "version mismatch":
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": "Resource was modified since it was retrieved",
+		})
+	case "article already in a series":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Article already belongs to a series",
+		})
+	case "article not in series":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Article is not part of this series",
+		})
+	case "parts mismatch":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "article_ids must list every article of the series exactly once",
+		})
+	}
+	log.Printf("error updating series: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to update series",
+	})
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// isID reports whether a route parameter is an entity ID rather than a slug.
+func isID(param string) bool {
+	return uuid.Validate(param) == nil
+}
+
+// slugParam returns the route parameter key, an ID or a slug. Slugs may hold
+// letters outside ASCII, which arrive percent-encoded.
+func slugParam(c *fiber.Ctx, key string) string {
+	param := c.Params(key)
+	if unescaped, err := url.PathUnescape(param); err == nil {
+		return unescaped
+	}
+	return param
+}
+
+// articleParam returns the ID of the article named by the route parameter
+// key, which may be its ID or one of its current or retired slugs. Unknown
+// slugs are reported as "article not found".
+func articleParam(ctx context.Context, c *fiber.Ctx, articles repository.ArticleRepository, key string) (string, error) {
+	param := slugParam(c, key)
+	if isID(param) {
+		return strings.ToLower(param), nil
+	}
+	return articles.ResolveSlug(ctx, param)
+}
+
+// userParam is articleParam for users, reporting unknown slugs as "user not
+// found".
+func userParam(ctx context.Context, c *fiber.Ctx, users repository.UserRepository, key string) (string, error) {
+	param := slugParam(c, key)
+	if isID(param) {
+		return strings.ToLower(param), nil
+	}
+	return users.ResolveSlug(ctx, param)
+}
+
+// redirectToSlug answers a request naming an entity by one of its retired
+// slugs, in the route parameter key, with a permanent redirect to the current
+// one. It reports whether the redirect has been written. Requests by ID or
+// current slug are left alone.
+func redirectToSlug(c *fiber.Ctx, key, current string) bool {
+	requested := slugParam(c, key)
+	if isID(requested) || requested == current {
+		return false
+	}
+	location := strings.TrimSuffix(c.Path(), c.Params(key)) + url.PathEscape(current)
+	if query := string(c.Request().URI().QueryString()); query != "" {
+		location += "?" + query
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	c.Redirect(location, fiber.StatusMovedPermanently)
+	return true
+}
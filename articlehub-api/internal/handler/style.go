@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/gofiber/fiber/v2"
+)
+
+// StyleHandler serves the stylesheet that colours highlighted code blocks.
+// Article HTML only carries chroma's token classes, so clients can switch
+// themes, e.g. for dark mode, with ?theme= and no re-rendering.
+type StyleHandler struct {
+	// Theme is the chroma style served when none is requested.
+	Theme  string
+	MaxAge time.Duration
+
+	stylesheets sync.Map // theme name -> stylesheet
+}
+
+type stylesheet struct {
+	css  []byte
+	etag string
+}
+
+func NewStyleHandler(theme string, maxAge time.Duration) *StyleHandler {
+	return &StyleHandler{Theme: theme, MaxAge: maxAge}
+}
+
+func (h *StyleHandler) Highlight(c *fiber.Ctx) error {
+	theme := c.Query("theme", h.Theme)
+	sheet, err := h.stylesheet(theme)
+	if err != nil {
+		log.Printf("error generating %s stylesheet: %v", theme, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate stylesheet",
+		})
+	}
+	if sheet == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Unknown theme",
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(int(h.MaxAge.Seconds())))
+	if notModified(c, sheet.etag) {
+		return nil
+	}
+	c.Set(fiber.HeaderContentType, "text/css; charset=utf-8")
+	return c.Send(sheet.css)
+}
+
+// stylesheet returns the CSS for a chroma style, or nil if there is no style
+// with that name. Styles never change at runtime, so each is generated once.
+func (h *StyleHandler) stylesheet(theme string) (*stylesheet, error) {
+	if cached, ok := h.stylesheets.Load(theme); ok {
+		return cached.(*stylesheet), nil
+	}
+	style, ok := styles.Registry[theme]
+	if !ok {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := chromahtml.New(chromahtml.WithClasses(true)).WriteCSS(&buf, style); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	sheet := &stylesheet{css: buf.Bytes(), etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+	h.stylesheets.Store(theme, sheet)
+	return sheet, nil
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"articlehub-api/internal/content"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+	"articlehub-api/internal/syndication"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// feedSummaryLength is the length of the plain text summary of an item.
+const feedSummaryLength = 300
+
+type SyndicationHandler struct {
+	Articles repository.ArticleRepository
+	Users    repository.UserRepository
+	// SiteURL is the public address of the site, used for item links.
+	SiteURL   string
+	SiteTitle string
+	Limit     int
+	MaxAge    time.Duration
+}
+
+func NewSyndicationHandler(articles repository.ArticleRepository, users repository.UserRepository,
+	siteURL, siteTitle string, limit int, maxAge time.Duration) *SyndicationHandler {
+	return &SyndicationHandler{
+		Articles:  articles,
+		Users:     users,
+		SiteURL:   siteURL,
+		SiteTitle: siteTitle,
+		Limit:     limit,
+		MaxAge:    maxAge,
+	}
+}
+
+// SiteFeed serves the latest articles of the whole site.
+func (h *SyndicationHandler) SiteFeed(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	feed := &syndication.Feed{
+		Title:       h.SiteTitle,
+		Description: "Latest articles on " + h.SiteTitle,
+		Link:        h.SiteURL + "/articles",
+	}
+	return h.serve(ctx, c, feed, model.ArticleFilter{})
+}
+
+// AuthorFeed serves the latest articles of one author.
+func (h *SyndicationHandler) AuthorFeed(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	authorID, err := userParam(ctx, c, h.Users, "id")
+	if err != nil {
+		return userLookupError(c, err)
+	}
+	author, err := h.Users.GetUserById(ctx, authorID)
+	if err != nil {
+		return userLookupError(c, err)
+	}
+	feed := &syndication.Feed{
+		Title:       author.Name + " on " + h.SiteTitle,
+		Description: "Latest articles by " + author.Name,
+		Link:        h.SiteURL + "/articles?author=" + url.QueryEscape(author.ID),
+	}
+	return h.serve(ctx, c, feed, model.ArticleFilter{AuthorID: author.ID})
+}
+
+// TagFeed serves the latest articles carrying a tag. Aliases are followed
+// like in the article listing.
+func (h *SyndicationHandler) TagFeed(c *fiber.Ctx) error {
+	tag, _ := url.PathUnescape(c.Params("tag"))
+	tag = content.NormalizeTag(tag)
+	if tag == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid tag",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	feed := &syndication.Feed{
+		Title:       "#" + tag + " on " + h.SiteTitle,
+		Description: "Latest articles tagged " + tag,
+		Link:        h.SiteURL + "/articles?tag=" + url.QueryEscape(tag),
+	}
+	return h.serve(ctx, c, feed, model.ArticleFilter{Tags: []string{tag}})
+}
+
+// serve loads the published articles matching filter into feed and writes it
+// in the format named by the route. Items carry the full article HTML unless
+// content=summary is requested. Responses are cacheable and honour
+// If-None-Match and, without it, If-Modified-Since. Last-Modified is the
+// latest change to a listed article or removal of one from the feed.
+func (h *SyndicationHandler) serve(ctx context.Context, c *fiber.Ctx, feed *syndication.Feed, filter model.ArticleFilter) error {
+	format := c.Params("format")
+	contentType, ok := syndication.ContentTypes[format]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Unknown feed format, use rss, atom or json",
+		})
+	}
+	mode := c.Query("content", "full")
+	if mode != "full" && mode != "summary" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "content must be full or summary",
+		})
+	}
+
+	// Feeds are public: drafts are never included, even for their author.
+	filter.ViewerID = ""
+	filter.Limit = h.Limit
+	articles, err := h.Articles.GetArticles(ctx, filter)
+	if err != nil {
+		log.Printf("error building feed: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build feed",
+		})
+	}
+
+	feed.FeedURL = h.SiteURL + c.OriginalURL()
+	hash := sha256.New()
+	hash.Write([]byte(format + "\n" + mode + "\n"))
+	for _, article := range articles {
+		hash.Write([]byte(article.ID + "@" + strconv.FormatInt(article.UpdatedAt.UnixNano(), 10) + "\n"))
+		if article.UpdatedAt.After(feed.Updated) {
+			feed.Updated = article.UpdatedAt
+		}
+		feed.Items = append(feed.Items, h.feedItem(&article, mode == "full"))
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(int(h.MaxAge.Seconds())))
+	c.Set(fiber.HeaderVary, fiber.HeaderAcceptEncoding)
+	// The newest item does not move when an article leaves the feed, so
+	// removals count as changes too.
+	removedAt, err := h.Articles.GetRemovedAt(ctx, filter)
+	if err != nil {
+		log.Printf("error building feed: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build feed",
+		})
+	}
+	if removedAt.After(feed.Updated) {
+		feed.Updated = removedAt
+	}
+	if !feed.Updated.IsZero() {
+		c.Set(fiber.HeaderLastModified, feed.Updated.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c, `W/"`+hex.EncodeToString(hash.Sum(nil))[:32]+`"`) || unmodifiedSince(c, feed.Updated) {
+		return nil
+	}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now()
+	}
+
+	body, err := syndication.Render(feed, format)
+	if err != nil {
+		log.Printf("error rendering %s feed: %v", format, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to build feed",
+		})
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+func (h *SyndicationHandler) feedItem(article *model.Article, full bool) syndication.Item {
+	item := syndication.Item{
+		ID:        article.ID,
+		Title:     article.Title,
+		Link:      h.SiteURL + "/articles/" + url.PathEscape(article.Slug),
+		Author:    article.AuthorName,
+		Summary:   content.Summary(article.BodyHTML, feedSummaryLength),
+		Published: article.CreatedAt,
+		Updated:   article.UpdatedAt,
+		Tags:      article.Tags,
+	}
+	if article.PublishedAt != nil {
+		item.Published = *article.PublishedAt
+	}
+	if full {
+		item.ContentHTML = article.BodyHTML
+	}
+	return item
+}
+
+// unmodifiedSince answers If-Modified-Since, which is only consulted when the
+// request carries no If-None-Match. It reports whether a 304 has been
+// written.
+func unmodifiedSince(c *fiber.Ctx, lastModified time.Time) bool {
+	if c.Get(fiber.HeaderIfNoneMatch) != "" {
+		return false
+	}
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil || lastModified.IsZero() || lastModified.Truncate(time.Second).After(since) {
+		return false
+	}
+	c.Status(fiber.StatusNotModified)
+	return true
+}
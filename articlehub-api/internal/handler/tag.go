@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/content"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// TagHandler serves tag and category listings, and their administration.
+type TagHandler struct {
+	Repo repository.TagRepository
+}
+
+func NewTagHandler(repo repository.TagRepository) *TagHandler {
+	return &TagHandler{Repo: repo}
+}
+
+// ListTags returns the most used tags, optionally only those starting with
+// prefix, which makes it usable for autocompletion.
+func (h *TagHandler) ListTags(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 50)
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	// A prefix of nothing but punctuation normalises to nothing, and no tag
+	// can start with it.
+	prefix := content.NormalizeTag(c.Query("prefix"))
+	if prefix == "" && strings.TrimSpace(c.Query("prefix")) != "" {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"tags":  []model.Tag{},
+			"count": 0,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tags, err := h.Repo.ListTags(ctx, prefix, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve tags",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tags":  tags,
+		"count": len(tags),
+	})
+}
+
+func (h *TagHandler) ListAliases(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	aliases, err := h.Repo.ListAliases(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve tag aliases",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"aliases": aliases,
+		"count":   len(aliases),
+	})
+}
+
+// CreateAlias makes an alternative spelling resolve to a canonical tag.
+// Articles already using the alias as a tag are moved to the canonical one.
+func (h *TagHandler) CreateAlias(c *fiber.Ctx) error {
+	var req model.CreateTagAliasRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	alias, tag := content.NormalizeTag(req.Alias), content.NormalizeTag(req.Tag)
+	if alias == "" || tag == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Alias and tag are required",
+		})
+	}
+	if alias == tag {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Alias must differ from the tag",
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	created, err := h.Repo.CreateAlias(ctx, alias, tag)
+	if err != nil {
+		if err.Error() == "alias would point to itself" {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Tag is already an alias of " + alias,
+			})
+		}
+		log.Printf("error creating tag alias: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create tag alias",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Tag alias created successfully",
+		"alias":   created,
+	})
+}
+
+func (h *TagHandler) DeleteAlias(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.DeleteAlias(ctx, content.NormalizeTag(c.Params("alias"))); err != nil {
+		if err.Error() == "alias not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Tag alias not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete tag alias",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Tag alias deleted successfully",
+	})
+}
+
+// ListCategories returns the category tree.
+func (h *TagHandler) ListCategories(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	categories, err := h.Repo.ListCategories(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve categories",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"categories": model.CategoryTree(categories),
+		"count":      len(categories),
+	})
+}
+
+func (h *TagHandler) CreateCategory(c *fiber.Ctx) error {
+	var req model.CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if msg := validateCategory(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate category ID",
+		})
+	}
+
+	category := &model.Category{
+		ID:          id.String(),
+		ParentID:    req.ParentID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.CreateCategory(ctx, category); err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":  "Category created successfully",
+		"category": category,
+	})
+}
+
+// UpdateCategory renames or moves a category. A category cannot be moved
+// under itself or one of its descendants.
+func (h *TagHandler) UpdateCategory(c *fiber.Ctx) error {
+	var req model.CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if msg := validateCategory(&req); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": msg,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	category, err := h.Repo.GetCategory(ctx, c.Params("id"))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	if req.ParentID != nil {
+		cycle, err := h.Repo.IsDescendant(ctx, *req.ParentID, category.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update category",
+			})
+		}
+		if cycle {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "A category cannot be moved under itself or its subcategories",
+			})
+		}
+	}
+
+	category.ParentID = req.ParentID
+	category.Name = req.Name
+	category.Description = req.Description
+
+	if err := h.Repo.UpdateCategory(ctx, category); err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":  "Category updated successfully",
+		"category": category,
+	})
+}
+
+func (h *TagHandler) DeleteCategory(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.DeleteCategory(ctx, c.Params("id")); err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Category deleted successfully",
+	})
+}
+
+// validateCategory trims the request and returns a message describing the
+// first problem found, or an empty string.
+func validateCategory(req *model.CategoryRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Name == "" {
+		return "Name is required"
+	}
+	if len([]rune(req.Name)) > 100 {
+		return "Name must be at most 100 characters"
+	}
+	if req.ParentID != nil && uuid.Validate(*req.ParentID) != nil {
+		return "Invalid parent category ID"
+	}
+	return ""
+}
+
+func categoryError(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "category not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	case "parent category not found":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parent category not found",
+		})
+	case "category already exists":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "A category with this name already exists under the same parent",
+		})
+	case "category has subcategories":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Category still has subcategories",
+		})
+	}
+	log.Printf("error writing category: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to save category",
+	})
+}
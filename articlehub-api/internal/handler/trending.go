@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/content"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TrendingHandler struct {
+	Repo repository.TrendingRepository
+}
+
+func NewTrendingHandler(repo repository.TrendingRepository) *TrendingHandler {
+	return &TrendingHandler{Repo: repo}
+}
+
+// GetTrending ranks published articles by recent activity. window is one of
+// day, week (the default) or month, and tag restricts the ranking to the
+// articles with that tag. Results are paginated with limit and offset.
+func (h *TrendingHandler) GetTrending(c *fiber.Ctx) error {
+	window, ok := model.TrendingWindows[c.Query("window", "week")]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid window, use day, week or month",
+		})
+	}
+
+	filter := model.TrendingFilter{
+		Limit:  c.QueryInt("limit", 20),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if tag := c.Query("tag"); tag != "" {
+		if filter.Tag = content.NormalizeTag(tag); filter.Tag == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid tag",
+			})
+		}
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	articles, err := h.Repo.GetTrending(ctx, window, filter, time.Now())
+	if err != nil {
+		log.Printf("error listing trending articles: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve trending articles",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"window":   window.Name,
+		"articles": articles,
+		"count":    len(articles),
+	})
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+
+	"articlehub-api/internal/images"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// formOverhead is the room left in an upload request, beyond the file
+// itself, for the other form fields and the multipart framing.
+const formOverhead = 64 << 10
+
+var (
+	errNotMultipart   = errors.New("not a multipart form")
+	errNoFile         = errors.New("no file uploaded")
+	errUploadTooLarge = errors.New("upload too large")
+)
+
+// uploadReadError is a failure to read the request body, as opposed to
+// one of the store the file is passed on to.
+type uploadReadError struct {
+	err error
+}
+
+func (e *uploadReadError) Error() string { return "failed to read upload: " + e.err.Error() }
+
+func (e *uploadReadError) Unwrap() error { return e.err }
+
+// streamImage reads a multipart/form-data request body part by part without
+// buffering it. The image in the file field is handed to handle as soon as
+// it is reached, once its magic bytes have shown it to be a supported
+// format; handle gets an error from the reader if the file grows past limit
+// bytes. The values of the other fields are returned.
+func streamImage(c *fiber.Ctx, field string, limit int64, handle func(file io.Reader, format images.Format) error) (map[string]string, error) {
+	boundary := string(c.Request().Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, errNotMultipart
+	}
+	// A body declaring its size can be turned down before it is read.
+	if n := c.Request().Header.ContentLength(); n > 0 && int64(n) > limit+formOverhead {
+		return nil, errUploadTooLarge
+	}
+
+	var body io.Reader
+	if stream := c.Context().RequestBodyStream(); stream != nil {
+		body = stream
+	} else {
+		body = bytes.NewReader(c.Body())
+	}
+	body = &limitedReader{r: body, n: limit + formOverhead}
+	form := multipart.NewReader(body, boundary)
+
+	values := map[string]string{}
+	found := false
+	for {
+		part, err := form.NextPart()
+		if err == io.EOF {
+			// Whatever follows the closing boundary is read too, so the
+			// connection is left at the start of the next request.
+			if _, err := io.Copy(io.Discard, body); err != nil {
+				return nil, readError(err)
+			}
+			break
+		}
+		if err != nil {
+			return nil, readError(err)
+		}
+
+		if part.FormName() == field && part.FileName() != "" && !found {
+			found = true
+			file := bufio.NewReaderSize(&limitedReader{r: part, n: limit}, 4096)
+			header, err := file.Peek(images.SniffLen)
+			if err != nil && err != io.EOF {
+				return nil, readError(err)
+			}
+			format, err := images.Sniff(header)
+			if err != nil {
+				return nil, err
+			}
+			if err := handle(&readErrorReader{file}, format); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if name := part.FormName(); name != "" && part.FileName() == "" {
+			value, err := io.ReadAll(part)
+			if err != nil {
+				return nil, readError(err)
+			}
+			values[name] = string(value)
+		}
+	}
+	if !found {
+		return nil, errNoFile
+	}
+	return values, nil
+}
+
+func readError(err error) error {
+	if errors.Is(err, errUploadTooLarge) {
+		return errUploadTooLarge
+	}
+	return &uploadReadError{err}
+}
+
+// readErrorReader marks the errors of reading the upload, so they can be
+// told from those of the code it is handed to.
+type readErrorReader struct {
+	r io.Reader
+}
+
+func (r *readErrorReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = readError(err)
+	}
+	return n, err
+}
+
+// limitedReader reads from r until more than n bytes have been read, then
+// fails with errUploadTooLarge.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errUploadTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return 0, errUploadTooLarge
+	}
+	return n, err
+}
+
+// uploadError answers a failed upload of at most limit bytes. The rest of
+// the body may be left unread, so the connection is closed rather than
+// having it taken for the next request.
+func uploadError(c *fiber.Ctx, err error, limit int64) error {
+	c.Context().SetConnectionClose()
+	var readErr *uploadReadError
+	switch {
+	case errors.Is(err, errUploadTooLarge):
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error": fmt.Sprintf("File is too large, the limit is %s", formatSize(limit)),
+		})
+	case errors.Is(err, errNotMultipart):
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "Request body must be multipart/form-data",
+		})
+	case errors.Is(err, errNoFile):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No file uploaded",
+		})
+	case errors.Is(err, images.ErrUnsupported), errors.Is(err, images.ErrTooLarge):
+		return imageError(c, err)
+	case errors.As(err, &readErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Failed to read file",
+		})
+	}
+	return storageError(c, err)
+}
+
+// formatSize writes a byte count in the largest unit it is a whole number
+// of, such as 5 MB.
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
@@ -3,19 +3,17 @@ package handler
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"io"
 	"log"
-	"mime/multipart"
-	"net/http"
-	"net/textproto"
-	"os"
-	"path/filepath"
 	"time"
 
+	"articlehub-api/internal/audit"
 	"articlehub-api/internal/auth"
+	"articlehub-api/internal/images"
+	"articlehub-api/internal/middleware"
 	"articlehub-api/internal/model"
 	"articlehub-api/internal/repository"
+	"articlehub-api/internal/storage"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -24,10 +22,21 @@ import (
 
 type UserHandler struct {
 	Repo repository.UserRepository
+	// GracePeriod is how long a deactivated account can still be restored
+	// by logging in before it is purged.
+	GracePeriod time.Duration
+	Audit       *audit.Service
+	// Storage keeps the avatars.
+	Storage storage.Storage
+	// AvatarMaxSize is the largest avatar, in bytes, that can be uploaded.
+	AvatarMaxSize int64
+	Decoder       *images.Decoder
 }
 
-func NewUserHandler(repo repository.UserRepository) *UserHandler {
-	return &UserHandler{Repo: repo}
+func NewUserHandler(repo repository.UserRepository, gracePeriod time.Duration, auditService *audit.Service,
+	store storage.Storage, avatarMaxSize int64, decoder *images.Decoder) *UserHandler {
+	return &UserHandler{Repo: repo, GracePeriod: gracePeriod, Audit: auditService, Storage: store,
+		AvatarMaxSize: avatarMaxSize, Decoder: decoder}
 }
 
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
@@ -81,6 +90,13 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	h.Audit.Record(c, model.AuditEntry{
+		ActorID:    user.ID,
+		Action:     model.AuditActionUserCreate,
+		TargetType: "user",
+		TargetID:   user.ID,
+	})
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "User created successfully",
 		"user": &model.User{
@@ -106,28 +122,78 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 
 	user, err := h.Repo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
+		h.Audit.Record(c, model.AuditEntry{
+			Action:     model.AuditActionUserLoginFailed,
+			TargetType: "email",
+			TargetID:   req.Email,
+		})
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Invalid Email",
 		})
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		h.Audit.Record(c, model.AuditEntry{
+			Action:     model.AuditActionUserLoginFailed,
+			TargetType: "user",
+			TargetID:   user.ID,
+		})
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Invalid Password",
 		})
 	}
 
-	token, err := auth.CreateToken(user.ID)
+	if user.SuspendedAt != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Account suspended",
+		})
+	}
+
+	message := "Login successful"
+	if user.DeletedAt != nil {
+		// Logging in during the grace period restores a deactivated account
+		if time.Since(*user.DeletedAt) > h.GracePeriod {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Account has been deleted",
+			})
+		}
+		if err := h.Repo.RestoreUser(ctx, user.ID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to restore account",
+			})
+		}
+		h.Audit.Record(c, model.AuditEntry{
+			ActorID:    user.ID,
+			Action:     model.AuditActionUserRestore,
+			TargetType: "user",
+			TargetID:   user.ID,
+		})
+		message = "Login successful, account restored"
+	}
+
+	token, err := auth.CreateToken(user.ID, user.SessionGeneration)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to generate token",
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Login successful",
-		"token":   token,
+	h.Audit.Record(c, model.AuditEntry{
+		ActorID:    user.ID,
+		Action:     model.AuditActionUserLogin,
+		TargetType: "user",
+		TargetID:   user.ID,
 	})
+
+	resp := fiber.Map{
+		"message": message,
+		"token":   token,
+	}
+	if user.PasswordResetRequired {
+		resp["password_reset_required"] = true
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
 }
 
 func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
@@ -148,7 +214,7 @@ func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) GetUserById(c *fiber.Ctx) error {
-	id := c.Params("id")
+	id := slugParam(c, "id")
 	if id == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "User ID is required",
@@ -158,7 +224,12 @@ func (h *UserHandler) GetUserById(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	user, err := h.Repo.GetUserById(ctx, id)
+	// Profiles can also be looked up by slug, retired ones redirecting.
+	userID, err := userParam(ctx, c, h.Repo, "id")
+	if err != nil {
+		return userLookupError(c, err)
+	}
+	user, err := h.Repo.GetUserById(ctx, userID)
 	if err != nil {
 		if err.Error() == "user not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -169,6 +240,13 @@ func (h *UserHandler) GetUserById(c *fiber.Ctx) error {
 			"error": "Failed to retrieve user",
 		})
 	}
+	if redirectToSlug(c, "id", user.Slug) {
+		return nil
+	}
+
+	if notModified(c, etag(user.Version)) {
+		return nil
+	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"user":    user,
@@ -178,139 +256,246 @@ func (h *UserHandler) GetUserById(c *fiber.Ctx) error {
 
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	id := c.Params("id")
-	fileHeader, err := c.FormFile("avatar")
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "No file uploaded",
+	if id != middleware.CurrentUserID(c) {
+		// O corpo não foi lido e não pode ser tomado pela próxima
+		// requisição da conexão.
+		c.Context().SetConnectionClose()
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only update your own profile",
 		})
 	}
 
-	file, err := fileHeader.Open()
+	// Confere a versão (If-Match) antes de enviar o avatar
+	lookupCtx, lookupCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer lookupCancel()
+
+	existingUser, err := h.Repo.GetUserById(lookupCtx, id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to open file",
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
 		})
 	}
-	defer file.Close()
+	if preconditionFailed(c, existingUser.Version) {
+		return nil
+	}
 
-	fileBytes, err := io.ReadAll(file)
+	uploadCtx, uploadCancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer uploadCancel()
+
+	// O avatar é pequeno (AvatarMaxSize) e é lido inteiro para ser
+	// decodificado: o tipo vem do conteúdo, nunca da extensão do nome, a
+	// orientação EXIF é aplicada e os metadados (localização, aparelho) são
+	// removidos antes de ele ficar público.
+	var data []byte
+	values, err := streamImage(c, "avatar", h.AvatarMaxSize, func(file io.Reader, _ images.Format) error {
+		var err error
+		data, err = io.ReadAll(file)
+		return err
+	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to read file",
-		})
+		return uploadError(c, err, h.AvatarMaxSize)
+	}
+	img, release, err := h.Decoder.Decode(uploadCtx, data)
+	if err != nil {
+		return imageError(c, err)
+	}
+	avatar, err := img.Original()
+	release()
+	if err != nil {
+		return imageError(c, err)
+	}
+	avatarKey := "avatars/" + uuid.NewString() + img.Ext
+	if err := h.Storage.Put(uploadCtx, avatarKey, img.ContentType, bytes.NewReader(avatar)); err != nil {
+		return storageError(c, err)
 	}
 
-	ext := filepath.Ext(fileHeader.Filename)
-	fileName := fmt.Sprintf("%s%s", uuid.NewString(), ext)
+	// Atualiza dados do usuário
+	reqBody := model.UpdateUserRequest{
+		Name:  values["name"],
+		Email: values["email"],
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	// Detecta o tipo MIME real do arquivo
-	contentType := http.DetectContentType(fileBytes)
+	before := *existingUser
 
-	// Monta multipart form com cabeçalho correto
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	if reqBody.Name != "" {
+		existingUser.Name = reqBody.Name
+	}
+	if reqBody.Email != "" {
+		existingUser.Email = reqBody.Email
+	}
 
-	partHeader := textproto.MIMEHeader{}
-	partHeader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, fileName))
-	partHeader.Set("Content-Type", contentType)
+	// Salva a URL do avatar
+	existingUser.AvatarURL = h.Storage.URL(avatarKey)
 
-	part, err := writer.CreatePart(partHeader)
-	if err != nil {
+	if err := h.Repo.UpdateUser(ctx, id, existingUser); err != nil {
+		// O novo avatar não chegou a ser usado
+		h.discard(avatarKey)
+		if err.Error() == "version mismatch" {
+			return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+				"error": "Resource was modified since it was retrieved",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create form part",
+			"error": "Failed to update user",
 		})
 	}
-	part.Write(fileBytes)
-	writer.Close()
+	c.Set(fiber.HeaderETag, etag(existingUser.Version))
 
-	// Faz o upload para o Supabase
-	req, err := http.NewRequest("POST",
-		fmt.Sprintf("%s/storage/v1/object/%s/%s", os.Getenv("S3_BUCKET_ENDPOINT"), os.Getenv("S3_BUCKET_NAME"), fileName),
-		body,
-	)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create upload request",
-		})
+	// O avatar anterior não é mais referenciado
+	if key, ok := h.Storage.Key(before.AvatarURL); ok {
+		h.discard(key)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("S3_BUCKET_SERVICE_ROLE"))
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionUserUpdate,
+		TargetType: "user",
+		TargetID:   id,
+		Changes:    audit.Diff(before, existingUser),
+	})
 
-	client := &http.Client{Timeout: time.Second * 10}
-	resp, err := client.Do(req)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to upload file to storage",
-		})
-	}
-	defer resp.Body.Close()
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "User updated successfully",
+		"user":    existingUser,
+	})
+}
 
-	if resp.StatusCode >= 400 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return c.Status(resp.StatusCode).SendString(string(respBody))
+func (h *UserHandler) discard(keys ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.Storage.Delete(ctx, keys...); err != nil {
+		log.Printf("error deleting stored avatar %v: %v", keys, err)
 	}
+}
 
-	// Gera a URL pública do avatar
-	avatarURL := fmt.Sprintf("%s/storage/v1/object/public/%s//%s",
-		os.Getenv("S3_BUCKET_ENDPOINT"),
-		os.Getenv("S3_BUCKET_NAME"),
-		fileName,
-	)
+// ChangePassword replaces the caller's password. Every other session is
+// signed out, so a fresh token is returned.
+func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id != middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only change your own password",
+		})
+	}
 
-	// Atualiza dados do usuário
-	var reqBody model.UpdateUserRequest
-	if err := c.BodyParser(&reqBody); err != nil {
+	var req model.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
+	if len(req.NewPassword) < 6 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "New password must have at least 6 characters",
+		})
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	existingUser, err := h.Repo.GetUserById(ctx, id)
+	user, err := h.Repo.GetUserCredentials(ctx, id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "User not found",
 		})
 	}
 
-	if reqBody.Name != "" {
-		existingUser.Name = reqBody.Name
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid Password",
+		})
 	}
-	if reqBody.Email != "" {
-		existingUser.Email = reqBody.Email
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to hash password",
+		})
 	}
 
-	// Salva a URL do avatar
-	existingUser.AvatarURL = avatarURL
+	generation, err := h.Repo.UpdatePassword(ctx, id, string(hash))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update password",
+		})
+	}
 
-	if err := h.Repo.UpdateUser(ctx, id, existingUser); err != nil {
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionPasswordChange,
+		TargetType: "user",
+		TargetID:   id,
+	})
+
+	token, err := auth.CreateToken(id, generation)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update user",
+			"error": "Failed to generate token",
 		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "User updated successfully",
-		"user":    existingUser,
+		"message": "Password changed successfully",
+		"token":   token,
 	})
 }
 
+// DeleteUser deactivates the caller's own account. The account is hidden
+// immediately and permanently purged once the grace period has elapsed,
+// unless the user logs in again before then.
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	id := c.Params("id")
+	if id != middleware.CurrentUserID(c) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only delete your own account",
+		})
+	}
+
+	var req model.DeactivateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if req.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Password is required",
+		})
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := h.Repo.DeleteUser(ctx, id); err != nil {
+	user, err := h.Repo.GetUserCredentials(ctx, id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid Password",
+		})
+	}
+
+	deletedAt, err := h.Repo.DeactivateUser(ctx, id)
+	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "User not found",
 		})
 	}
 
+	h.Audit.Record(c, model.AuditEntry{
+		Action:     model.AuditActionUserDeactivate,
+		TargetType: "user",
+		TargetID:   id,
+	})
+
 	return c.JSON(fiber.Map{
-		"message": "User deleted successfully",
+		"message":     "Account deactivated, log in again before the purge date to restore it",
+		"purge_after": deletedAt.Add(h.GracePeriod),
 	})
 }
@@ -0,0 +1,225 @@
+// Package images validates uploaded images and scales them down to the
+// widths served to browsers through srcset.
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels bounds the size of the images accepted, which are fully decoded
+// in memory to be scaled: up to 4 bytes a pixel, 8 for 16-bit PNGs, plus the
+// copies made to turn them upright and scale them.
+const MaxPixels = 24_000_000
+
+const jpegQuality = 85
+
+var (
+	ErrUnsupported = errors.New("unsupported image format")
+	ErrTooLarge    = errors.New("image dimensions too large")
+)
+
+// Format describes how an image is stored and served.
+type Format struct {
+	ContentType string
+	Ext         string
+}
+
+var formats = map[string]Format{
+	"jpeg": {ContentType: "image/jpeg", Ext: ".jpg"},
+	"png":  {ContentType: "image/png", Ext: ".png"},
+	"gif":  {ContentType: "image/gif", Ext: ".gif"},
+	"webp": {ContentType: "image/webp", Ext: ".webp"},
+}
+
+// Image is a decoded upload.
+type Image struct {
+	Format
+	Width  int
+	Height int
+
+	img  image.Image
+	name string
+	data []byte
+	// rotated is set when img was turned upright from the EXIF orientation
+	// of data, which then no longer matches it.
+	rotated bool
+}
+
+type Variant struct {
+	Format
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// SniffLen is the number of leading bytes Sniff needs.
+const SniffLen = 12
+
+// Sniff tells the format of a JPEG, PNG, GIF or WebP file from the magic
+// bytes it starts with, so an upload can be turned down before the rest of
+// it is read.
+func Sniff(header []byte) (Format, error) {
+	switch {
+	case bytes.HasPrefix(header, []byte("\xff\xd8\xff")):
+		return formats["jpeg"], nil
+	case bytes.HasPrefix(header, []byte("\x89PNG\r\n\x1a\n")):
+		return formats["png"], nil
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return formats["gif"], nil
+	case len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return formats["webp"], nil
+	}
+	return Format{}, ErrUnsupported
+}
+
+// Decoder bounds how many images are decoded and held in memory at once, so
+// that concurrent uploads cannot exhaust it.
+type Decoder struct {
+	slots chan struct{}
+}
+
+// NewDecoder returns a Decoder letting n images be processed at a time.
+func NewDecoder(n int) *Decoder {
+	return &Decoder{slots: make(chan struct{}, max(1, n))}
+}
+
+// Decode waits for a free slot, or for ctx to be done, and decodes data like
+// the package level Decode. Unless an error is returned, release must be
+// called once the image and its variants are no longer needed.
+func (d *Decoder) Decode(ctx context.Context, data []byte) (img *Image, release func(), err error) {
+	select {
+	case d.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+	release = func() { <-d.slots }
+	img, err = Decode(data)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return img, release, nil
+}
+
+// Decode reads a JPEG, PNG, GIF or WebP image. The format is told from the
+// data itself, never from a file name or a declared content type. JPEGs are
+// turned upright according to their EXIF orientation, which Width, Height
+// and the variants reflect.
+func Decode(data []byte) (*Image, error) {
+	config, name, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	decoded := &Image{Format: formats[name], Width: config.Width, Height: config.Height, img: img, name: name, data: data}
+	if name == "jpeg" {
+		if orientation := jpegOrientation(data); orientation > 1 {
+			decoded.img = orient(img, orientation)
+			decoded.Width, decoded.Height = decoded.img.Bounds().Dx(), decoded.img.Bounds().Dy()
+			decoded.rotated = true
+		}
+	}
+	return decoded, nil
+}
+
+// Original returns the upload as it is to be stored and served, without
+// the metadata it carried: EXIF in particular can tell where a photo was
+// taken and with which device. Metadata is cut out without re-encoding the
+// image, except for a JPEG that had to be turned upright, which is
+// re-encoded as the orientation it relied on is dropped with the rest.
+func (i *Image) Original() ([]byte, error) {
+	var stripped []byte
+	switch i.name {
+	case "jpeg":
+		if i.rotated {
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, i.img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		}
+		stripped = stripJPEG(i.data)
+	case "png":
+		stripped = stripPNG(i.data)
+	case "webp":
+		stripped = stripWebP(i.data)
+	default:
+		// GIFs have no EXIF, and re-encoding them would lose their
+		// animation.
+		return i.data, nil
+	}
+	if stripped == nil {
+		return nil, ErrUnsupported
+	}
+	return stripped, nil
+}
+
+func decodeConfig(r io.Reader) (image.Config, string, error) {
+	config, name, err := image.DecodeConfig(r)
+	if err != nil {
+		return config, "", ErrUnsupported
+	}
+	if _, ok := formats[name]; !ok {
+		return config, "", ErrUnsupported
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return config, "", ErrUnsupported
+	}
+	if config.Width*config.Height > MaxPixels {
+		return config, "", ErrTooLarge
+	}
+	return config, name, nil
+}
+
+// Variants scales the image down to every width narrower than the image,
+// keeping its aspect ratio. PNGs stay PNGs so transparency survives, other
+// formats become JPEGs; GIFs have no variants as scaling would drop their
+// animation.
+func (i *Image) Variants(widths []int) ([]Variant, error) {
+	if i.name == "gif" {
+		return nil, nil
+	}
+	widths = append([]int(nil), widths...)
+	sort.Ints(widths)
+
+	var variants []Variant
+	for n, width := range widths {
+		if width <= 0 || width >= i.Width || (n > 0 && width == widths[n-1]) {
+			continue
+		}
+		height := max(1, (i.Height*width+i.Width/2)/i.Width)
+		scaled := image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(scaled, scaled.Bounds(), i.img, i.img.Bounds(), draw.Src, nil)
+
+		variant := Variant{Width: width, Height: height}
+		var buf bytes.Buffer
+		var err error
+		if i.name == "png" {
+			variant.Format = formats["png"]
+			err = png.Encode(&buf, scaled)
+		} else {
+			variant.Format = formats["jpeg"]
+			err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			return nil, err
+		}
+		variant.Data = buf.Bytes()
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
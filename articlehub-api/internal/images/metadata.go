@@ -0,0 +1,223 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+// JPEG markers of the segments kept by stripJPEG. APP0 (JFIF), APP2 (ICC
+// colour profile) and APP14 (Adobe colour transform) affect how the image
+// is shown; the other application segments and comments only carry
+// metadata such as EXIF, XMP and IPTC.
+const (
+	markerAPP0  = 0xe0
+	markerAPP1  = 0xe1
+	markerAPP2  = 0xe2
+	markerAPP14 = 0xee
+	markerAPP15 = 0xef
+	markerCOM   = 0xfe
+	markerSOS   = 0xda
+)
+
+// stripJPEG returns data without its metadata segments. Everything from the
+// start of scan on is copied unchanged, so the image itself is not
+// re-encoded. It returns nil if the segments cannot be parsed.
+func stripJPEG(data []byte) []byte {
+	if !bytes.HasPrefix(data, []byte{0xff, 0xd8}) {
+		return nil
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	for i := 2; i < len(data); {
+		start := i
+		if data[i] != 0xff {
+			return nil
+		}
+		for i < len(data) && data[i] == 0xff {
+			i++
+		}
+		if i+3 > len(data) {
+			return nil
+		}
+		marker := data[i]
+		length := int(binary.BigEndian.Uint16(data[i+1:]))
+		end := i + 1 + length
+		if length < 2 || end > len(data) {
+			return nil
+		}
+		if marker == markerSOS {
+			return append(out, data[start:]...)
+		}
+		metadata := marker == markerCOM ||
+			(marker >= markerAPP1 && marker <= markerAPP15 && marker != markerAPP2 && marker != markerAPP14)
+		if !metadata {
+			out = append(out, data[start:end]...)
+		}
+		i = end
+	}
+	return nil
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG, from 1 to 8, or 1
+// when it has none.
+func jpegOrientation(data []byte) int {
+	for i := 2; i+4 <= len(data) && data[i] == 0xff; {
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if marker == markerSOS || length < 2 || end > len(data) {
+			break
+		}
+		if segment := data[i+4 : end]; marker == markerAPP1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i = end
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF
+// structure, the form EXIF data takes.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		const tagOrientation, typeShort = 0x0112, 3
+		if order.Uint16(tiff[entry:]) == tagOrientation && order.Uint16(tiff[entry+2:]) == typeShort {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			break
+		}
+	}
+	return 1
+}
+
+// orient turns img upright according to an EXIF orientation. Orientations 5
+// to 8 swap the width and the height.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // rotated 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated 90° counter clockwise, to be turned clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90° clockwise, to be turned counter clockwise
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
+
+// PNG chunks dropped by stripPNG: EXIF, textual metadata and the time of the
+// last edit.
+var pngMetadata = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+// stripPNG returns data without its metadata chunks, or nil if the chunks
+// cannot be parsed.
+func stripPNG(data []byte) []byte {
+	const signature = 8
+	if len(data) < signature {
+		return nil
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:signature]...)
+	for i := signature; i < len(data); {
+		if i+8 > len(data) {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil
+		}
+		if !pngMetadata[string(data[i+4:i+8])] {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	return out
+}
+
+// stripWebP returns data without its EXIF and XMP chunks, clearing their
+// flags in the extended header, or nil if the chunks cannot be parsed.
+func stripWebP(data []byte) []byte {
+	const header = 12
+	if len(data) < header {
+		return nil
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:header]...)
+	for i := header; i < len(data); {
+		if i+8 > len(data) {
+			return nil
+		}
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size%2
+		if size < 0 || end > len(data) {
+			return nil
+		}
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[i:end]...)
+			if len(chunk) > 8 {
+				const exifFlag, xmpFlag = 0x08, 0x04
+				chunk[8] &^= exifFlag | xmpFlag
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out
+}
@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/repository"
+	"articlehub-api/internal/storage"
+)
+
+// purgedObjectBatch is how many stored objects are removed per request to
+// storage.
+const purgedObjectBatch = 100
+
+// AccountPurger permanently removes accounts whose deactivation grace period
+// has elapsed, along with the files they had stored.
+type AccountPurger struct {
+	Repo        repository.UserRepository
+	Storage     storage.Storage
+	GracePeriod time.Duration
+	Interval    time.Duration
+	// Anonymize scrubs personal data but keeps the row instead of deleting it.
+	Anonymize bool
+}
+
+// Run purges expired accounts every Interval until ctx is cancelled.
+func (p *AccountPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *AccountPurger) purge(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	n, err := p.Repo.PurgeDeactivatedUsers(ctx, time.Now().Add(-p.GracePeriod), p.Anonymize)
+	if err != nil {
+		log.Printf("error purging deactivated accounts: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("purged %d deactivated accounts", n)
+	}
+
+	p.removeObjects(ctx)
+}
+
+// removeObjects deletes the stored files of purged accounts from storage.
+// Objects stay queued until storage has deleted them, so a failure is only
+// retried on the next run.
+func (p *AccountPurger) removeObjects(ctx context.Context) {
+	for ctx.Err() == nil {
+		objects, err := p.Repo.ListPurgedObjects(ctx, purgedObjectBatch)
+		if err != nil {
+			log.Printf("error listing files of purged accounts: %v", err)
+			return
+		}
+		if len(objects) == 0 {
+			return
+		}
+
+		ids := make([]int64, 0, len(objects))
+		keys := make([]string, 0, len(objects))
+		for _, object := range objects {
+			ids = append(ids, object.ID)
+			// Avatars hosted elsewhere are not ours to delete and are only
+			// cleared from the queue.
+			if object.Key != "" {
+				keys = append(keys, object.Key)
+			} else if key, ok := p.Storage.Key(object.URL); ok {
+				keys = append(keys, key)
+			}
+		}
+
+		if err := p.Storage.Delete(ctx, keys...); err != nil {
+			log.Printf("error deleting files of purged accounts: %v", err)
+			return
+		}
+		if err := p.Repo.DeletePurgedObjects(ctx, ids); err != nil {
+			log.Printf("error clearing deleted files of purged accounts: %v", err)
+			return
+		}
+	}
+}
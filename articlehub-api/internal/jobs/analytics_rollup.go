@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/analytics"
+	"articlehub-api/internal/repository"
+)
+
+// AnalyticsRollup periodically folds the article visits of past days into
+// the daily counters and deletes them together with their salts.
+type AnalyticsRollup struct {
+	Repo     repository.AnalyticsRepository
+	Interval time.Duration
+}
+
+// Run rolls up visits every Interval until ctx is cancelled.
+func (r *AnalyticsRollup) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.rollup(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *AnalyticsRollup) rollup(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	n, err := r.Repo.Rollup(ctx, analytics.Day(time.Now()))
+	if err != nil {
+		log.Printf("error rolling up article visits: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("rolled up %d article visits", n)
+	}
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+
+	"articlehub-api/internal/repository"
+)
+
+// ArticleExport adds the user's articles to a data export, as JSON and as
+// one Markdown file per article.
+type ArticleExport struct {
+	Repo repository.ArticleRepository
+}
+
+func (e *ArticleExport) Export(ctx context.Context, userID string, zw *zip.Writer) error {
+	articles, err := e.Repo.GetArticlesByAuthor(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to export articles: %w", err)
+	}
+	if len(articles) == 0 {
+		return nil
+	}
+
+	if err := writeJSON(zw, "articles/articles.json", articles); err != nil {
+		return err
+	}
+	for _, article := range articles {
+		w, err := zw.Create(fmt.Sprintf("articles/%s.md", article.ID))
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, fmt.Sprintf("# %s\n\n%s\n", article.Title, article.Body)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/content"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+)
+
+// ArticleStatsBackfill renders the articles saved before word counts,
+// reading times and tables of contents were computed on write, and stores
+// the result. It stops once every article has been processed.
+type ArticleStatsBackfill struct {
+	Repo      repository.ArticleRepository
+	Renderer  *content.Renderer
+	BatchSize int
+}
+
+// Run processes batches of articles until none is left or ctx is cancelled.
+func (b *ArticleStatsBackfill) Run(ctx context.Context) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := b.backfill(ctx)
+		if err != nil {
+			log.Printf("error computing article stats: %v", err)
+			return
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+	}
+	if total > 0 {
+		log.Printf("computed stats for %d articles", total)
+	}
+}
+
+func (b *ArticleStatsBackfill) backfill(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	articles, err := b.Repo.GetArticlesWithoutStats(ctx, b.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range articles {
+		article := &articles[i]
+		// Articles this old predate the media library, so have no media
+		// references to resolve.
+		bodyHTML, stats, err := b.Renderer.RenderArticle(article.Body, nil)
+		if err != nil {
+			// Keep the stored HTML; empty stats still take the article off
+			// the backlog.
+			log.Printf("error rendering article %s: %v", article.ID, err)
+			stats = model.ArticleStats{TOC: []model.TOCEntry{}}
+		} else {
+			article.BodyHTML = bodyHTML
+		}
+		article.ArticleStats = stats
+		if err := b.Repo.UpdateStats(ctx, article); err != nil {
+			return 0, err
+		}
+	}
+	return len(articles), nil
+}
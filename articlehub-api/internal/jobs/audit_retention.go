@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/repository"
+)
+
+// AuditRetention deletes audit log entries older than the retention period.
+type AuditRetention struct {
+	Repo      repository.AuditRepository
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// Run deletes expired entries every Interval until ctx is cancelled.
+func (r *AuditRetention) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.prune(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *AuditRetention) prune(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	n, err := r.Repo.DeleteEntriesBefore(ctx, time.Now().Add(-r.Retention))
+	if err != nil {
+		log.Printf("error pruning audit log: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("pruned %d audit log entries", n)
+	}
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"articlehub-api/internal/repository"
+)
+
+// CommentExport adds the user's comments to a data export, as JSON and as
+// one Markdown file per comment.
+type CommentExport struct {
+	Repo repository.CommentRepository
+}
+
+func (e *CommentExport) Export(ctx context.Context, userID string, zw *zip.Writer) error {
+	comments, err := e.Repo.GetCommentsByAuthor(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to export comments: %w", err)
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+
+	if err := writeJSON(zw, "comments/comments.json", comments); err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		w, err := zw.Create(fmt.Sprintf("comments/%s.md", comment.ID))
+		if err != nil {
+			return err
+		}
+		header := fmt.Sprintf("- **Article:** %s\n- **Written:** %s\n", comment.ArticleID, comment.CreatedAt.Format(time.RFC3339))
+		if comment.ParentID != nil {
+			header += fmt.Sprintf("- **In reply to:** %s\n", *comment.ParentID)
+		}
+		if _, err := io.WriteString(w, header+"\n"+comment.Body+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
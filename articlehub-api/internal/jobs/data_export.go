@@ -0,0 +1,206 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+)
+
+const (
+	// buildTimeout bounds the building of one archive.
+	buildTimeout = 5 * time.Minute
+	// claimTimeout is how long an export may stay processing before it is
+	// assumed abandoned by a worker that died and is claimed again. It is
+	// well above buildTimeout so a live worker is never raced.
+	claimTimeout = 3 * buildTimeout
+)
+
+// ExportSource contributes a user's data to a data export archive. Each
+// feature that stores user-authored content registers one.
+type ExportSource interface {
+	// Export writes the user's data into the archive, typically under a
+	// directory named after the source.
+	Export(ctx context.Context, userID string, zw *zip.Writer) error
+}
+
+// DataExporter builds the ZIP archives requested through the data export
+// endpoints and cleans them up once their download link has expired.
+type DataExporter struct {
+	Users    repository.UserRepository
+	Exports  repository.ExportRepository
+	Sources  []ExportSource
+	Dir      string
+	TTL      time.Duration
+	Interval time.Duration
+}
+
+// Run processes pending exports every Interval until ctx is cancelled.
+func (e *DataExporter) Run(ctx context.Context) {
+	if err := os.MkdirAll(e.Dir, 0o750); err != nil {
+		log.Printf("error creating export directory: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		e.processPending(ctx)
+		e.removeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *DataExporter) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		export, err := e.Exports.ClaimPendingExport(ctx, time.Now().Add(-claimTimeout))
+		if err != nil {
+			log.Printf("error claiming data export: %v", err)
+			return
+		}
+		if export == nil {
+			return
+		}
+
+		filePath := filepath.Join(e.Dir, export.ID+".zip")
+		if err := e.build(ctx, export.UserID, filePath); err != nil {
+			log.Printf("error building data export %s: %v", export.ID, err)
+			os.Remove(filePath)
+			if err := e.Exports.FailExport(ctx, export.ID, "Failed to build export"); err != nil {
+				log.Printf("error marking data export %s as failed: %v", export.ID, err)
+			}
+			continue
+		}
+
+		if err := e.Exports.CompleteExport(ctx, export.ID, filePath, time.Now().Add(e.TTL)); err != nil {
+			log.Printf("error completing data export %s: %v", export.ID, err)
+		}
+	}
+}
+
+func (e *DataExporter) build(ctx context.Context, userID, filePath string) error {
+	ctx, cancel := context.WithTimeout(ctx, buildTimeout)
+	defer cancel()
+
+	user, err := e.Users.GetUserById(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	if err := writeJSON(zw, "profile.json", user); err != nil {
+		return err
+	}
+	if err := writeProfileMarkdown(zw, user); err != nil {
+		return err
+	}
+	if user.AvatarURL != "" {
+		// The avatar is a copy of what is already linked from the profile,
+		// so the export goes on without it rather than failing.
+		if err := writeRemoteFile(ctx, zw, "media/avatar"+path.Ext(user.AvatarURL), user.AvatarURL); err != nil {
+			log.Printf("error exporting avatar of user %s, leaving it out: %v", userID, err)
+		}
+	}
+	for _, source := range e.Sources {
+		if err := source.Export(ctx, userID, zw); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (e *DataExporter) removeExpired(ctx context.Context) {
+	paths, err := e.Exports.DeleteExpiredExports(ctx, time.Now())
+	if err != nil {
+		log.Printf("error deleting expired data exports: %v", err)
+		return
+	}
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			log.Printf("error removing data export file %s: %v", p, err)
+		}
+	}
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeProfileMarkdown(zw *zip.Writer, user *model.User) error {
+	w, err := zw.Create("profile.md")
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", user.Name)
+	fmt.Fprintf(&b, "- **ID:** %s\n", user.ID)
+	fmt.Fprintf(&b, "- **Email:** %s\n", user.Email)
+	if user.AvatarURL != "" {
+		fmt.Fprintf(&b, "- **Avatar:** %s\n", user.AvatarURL)
+	}
+	fmt.Fprintf(&b, "- **Member since:** %s\n", user.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Last updated:** %s\n", user.UpdatedAt.Format(time.RFC3339))
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func writeRemoteFile(ctx context.Context, zw *zip.Writer, name, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: time.Second * 30}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("storage responded with status %d", resp.StatusCode)
+	}
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
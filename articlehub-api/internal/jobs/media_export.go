@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"path"
+
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+)
+
+// mediaExportPage is how many media items are read at a time.
+const mediaExportPage = 500
+
+// MediaExport adds the user's media library to a data export, as JSON and
+// as the stored original of every item. Variants are left out, as they can
+// be derived from the originals.
+type MediaExport struct {
+	Repo repository.MediaRepository
+}
+
+func (e *MediaExport) Export(ctx context.Context, userID string, zw *zip.Writer) error {
+	var library []model.Media
+	for {
+		page, err := e.Repo.ListMedia(ctx, userID, mediaExportPage, len(library))
+		if err != nil {
+			return fmt.Errorf("failed to export media: %w", err)
+		}
+		library = append(library, page...)
+		if len(page) < mediaExportPage {
+			break
+		}
+	}
+	if len(library) == 0 {
+		return nil
+	}
+
+	if err := writeJSON(zw, "media/library.json", library); err != nil {
+		return err
+	}
+	for _, media := range library {
+		name := fmt.Sprintf("media/library/%s%s", media.ID, path.Ext(media.URL))
+		if err := writeRemoteFile(ctx, zw, name, media.URL); err != nil {
+			return fmt.Errorf("failed to export media %s: %w", media.ID, err)
+		}
+	}
+	return nil
+}
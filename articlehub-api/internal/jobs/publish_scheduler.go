@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/repository"
+)
+
+// PublishScheduler publishes scheduled articles once their publish_at date
+// has passed.
+type PublishScheduler struct {
+	Repo     repository.ArticleRepository
+	Interval time.Duration
+}
+
+// Run publishes due articles every Interval until ctx is cancelled.
+func (s *PublishScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.publish(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PublishScheduler) publish(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	n, err := s.Repo.PublishDueArticles(ctx, time.Now())
+	if err != nil {
+		log.Printf("error publishing scheduled articles: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("published %d scheduled articles", n)
+	}
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/repository"
+)
+
+// ReactionReconciler periodically recomputes the denormalised reaction
+// counters from the reactions themselves.
+type ReactionReconciler struct {
+	Repo     repository.ReactionRepository
+	Interval time.Duration
+}
+
+// Run reconciles the counters every Interval until ctx is cancelled.
+func (r *ReactionReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.reconcile(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ReactionReconciler) reconcile(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	n, err := r.Repo.ReconcileCounts(ctx)
+	if err != nil {
+		log.Printf("error reconciling reaction counts: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("corrected %d reaction counts", n)
+	}
+}
@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+)
+
+// bookmarkExportPage is how many bookmarks are read at a time.
+const bookmarkExportPage = 500
+
+// ReadingListExport adds the user's bookmarks and reading lists to a data
+// export, as JSON and as one Markdown file for the bookmarks and for each
+// list. As in the app, articles the user can no longer see are left out.
+type ReadingListExport struct {
+	Repo repository.ReadingListRepository
+}
+
+func (e *ReadingListExport) Export(ctx context.Context, userID string, zw *zip.Writer) error {
+	var bookmarks []model.Bookmark
+	for {
+		page, err := e.Repo.ListBookmarks(ctx, userID, bookmarkExportPage, len(bookmarks))
+		if err != nil {
+			return fmt.Errorf("failed to export bookmarks: %w", err)
+		}
+		bookmarks = append(bookmarks, page...)
+		if len(page) < bookmarkExportPage {
+			break
+		}
+	}
+	if len(bookmarks) > 0 {
+		if err := writeJSON(zw, "reading-lists/bookmarks.json", bookmarks); err != nil {
+			return err
+		}
+		var b strings.Builder
+		b.WriteString("# Bookmarks\n\n")
+		for _, bookmark := range bookmarks {
+			writeArticleEntry(&b, "-", bookmark.Article, bookmark.Note)
+		}
+		if err := writeMarkdown(zw, "reading-lists/bookmarks.md", b.String()); err != nil {
+			return err
+		}
+	}
+
+	lists, err := e.Repo.ListReadingLists(ctx, userID, false)
+	if err != nil {
+		return fmt.Errorf("failed to export reading lists: %w", err)
+	}
+	if len(lists) == 0 {
+		return nil
+	}
+	for i := range lists {
+		if lists[i].Items, err = e.Repo.ListItems(ctx, lists[i].ID, userID); err != nil {
+			return fmt.Errorf("failed to export reading list %s: %w", lists[i].ID, err)
+		}
+	}
+
+	if err := writeJSON(zw, "reading-lists/lists.json", lists); err != nil {
+		return err
+	}
+	for _, list := range lists {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n", list.Name)
+		if list.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", list.Description)
+		}
+		for _, item := range list.Items {
+			writeArticleEntry(&b, "1.", item.Article, item.Note)
+		}
+		if err := writeMarkdown(zw, fmt.Sprintf("reading-lists/%s.md", list.ID), b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArticleEntry writes a Markdown list entry for a saved article and the
+// note it was saved with.
+func writeArticleEntry(b *strings.Builder, marker string, article *model.Article, note string) {
+	fmt.Fprintf(b, "%s **%s** (%s)\n", marker, article.Title, article.ID)
+	if note != "" {
+		fmt.Fprintf(b, "   %s\n", strings.ReplaceAll(note, "\n", "\n   "))
+	}
+}
+
+func writeMarkdown(zw *zip.Writer, name, body string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, body)
+	return err
+}
@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/recommend"
+	"articlehub-api/internal/repository"
+)
+
+// RelatedArticles periodically recomputes the recommended next reads of
+// every published article.
+type RelatedArticles struct {
+	Repo repository.RelatedRepository
+	// Limit is the number of recommendations kept per article.
+	Limit    int
+	Interval time.Duration
+}
+
+// Run refreshes the recommendations every Interval until ctx is cancelled.
+func (r *RelatedArticles) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *RelatedArticles) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	docs, err := r.Repo.GetDocuments(ctx)
+	if err != nil {
+		log.Printf("error loading articles for recommendations: %v", err)
+		return
+	}
+
+	corpus := make([]recommend.Document, len(docs))
+	for i, doc := range docs {
+		corpus[i] = recommend.Document{ID: doc.ID, Title: doc.Title, Body: doc.Body, Tags: doc.Tags, Readers: doc.Readers}
+	}
+	var recommendations []model.Recommendation
+	for id, neighbours := range recommend.Neighbours(corpus, r.Limit, recommend.DefaultWeights) {
+		for _, n := range neighbours {
+			recommendations = append(recommendations, model.Recommendation{ArticleID: id, RelatedID: n.ID, Score: n.Score})
+		}
+	}
+
+	if err := r.Repo.ReplaceRecommendations(ctx, recommendations); err != nil {
+		log.Printf("error saving recommendations: %v", err)
+		return
+	}
+	log.Printf("refreshed recommendations for %d articles", len(docs))
+}
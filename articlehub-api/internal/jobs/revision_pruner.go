@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/repository"
+)
+
+// RevisionPruner deletes old article revisions according to the deployment's
+// retention rules. The latest revision of every article is always kept.
+type RevisionPruner struct {
+	Repo repository.ArticleRepository
+	// Keep is the number of revisions kept per article, 0 for no limit.
+	Keep int
+	// MaxAge deletes revisions older than this, 0 for no limit.
+	MaxAge   time.Duration
+	Interval time.Duration
+}
+
+// Run prunes revisions every Interval until ctx is cancelled.
+func (p *RevisionPruner) Run(ctx context.Context) {
+	if p.Keep <= 0 && p.MaxAge <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.prune(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *RevisionPruner) prune(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	var olderThan time.Time
+	if p.MaxAge > 0 {
+		olderThan = time.Now().Add(-p.MaxAge)
+	}
+
+	n, err := p.Repo.PruneRevisions(ctx, p.Keep, olderThan)
+	if err != nil {
+		log.Printf("error pruning article revisions: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("pruned %d article revisions", n)
+	}
+}
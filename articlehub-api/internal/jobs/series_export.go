@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"strings"
+
+	"articlehub-api/internal/repository"
+)
+
+// SeriesExport adds the user's series to a data export, as JSON and as one
+// Markdown file per series listing its parts in order.
+type SeriesExport struct {
+	Repo repository.SeriesRepository
+}
+
+func (e *SeriesExport) Export(ctx context.Context, userID string, zw *zip.Writer) error {
+	series, err := e.Repo.GetSeriesByAuthor(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to export series: %w", err)
+	}
+	if len(series) == 0 {
+		return nil
+	}
+	for i := range series {
+		// The user is the author, so drafts are listed too.
+		if series[i].Parts, err = e.Repo.ListParts(ctx, series[i].ID, userID); err != nil {
+			return fmt.Errorf("failed to export series %s: %w", series[i].ID, err)
+		}
+	}
+
+	if err := writeJSON(zw, "series/series.json", series); err != nil {
+		return err
+	}
+	for _, s := range series {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n", s.Title)
+		if s.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", s.Description)
+		}
+		for _, part := range s.Parts {
+			fmt.Fprintf(&b, "1. **%s** (%s, %s)\n", part.Title, part.ArticleID, part.Status)
+		}
+		if err := writeMarkdown(zw, fmt.Sprintf("series/%s.md", s.ID), b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
+)
+
+// TrendingRanker keeps the trending scores of every window up to date.
+type TrendingRanker struct {
+	Repo     repository.TrendingRepository
+	Interval time.Duration
+}
+
+// Run updates the scores every Interval until ctx is cancelled.
+func (r *TrendingRanker) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, window := range model.TrendingWindows {
+			r.update(ctx, window)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *TrendingRanker) update(ctx context.Context, window model.TrendingWindow) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	if _, err := r.Repo.UpdateScores(ctx, window, time.Now()); err != nil {
+		log.Printf("error updating %s trending scores: %v", window.Name, err)
+	}
+}
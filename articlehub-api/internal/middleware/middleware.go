@@ -1,17 +1,63 @@
 package middleware
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"articlehub-api/internal/auth"
+	"articlehub-api/internal/model"
+	"articlehub-api/internal/repository"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func Middleware() fiber.Handler {
+const (
+	userIDKey       = "userID"
+	userRoleKey     = "userRole"
+	impersonatorKey = "impersonatorID"
+
+	// HeaderImpersonatedBy is set on every response to an impersonated
+	// request, carrying the ID of the acting administrator.
+	HeaderImpersonatedBy = "X-Impersonated-By"
+)
+
+// ImpersonationRecorder records requests made with an impersonation token.
+type ImpersonationRecorder interface {
+	RecordImpersonatedRequest(c *fiber.Ctx)
+}
+
+// Middleware authenticates the request with its bearer token and rejects
+// suspended users, revoked sessions and users who must reset their password.
+// Impersonated requests are reported to recorder once handled.
+func Middleware(users repository.UserRepository, recorder ImpersonationRecorder) fiber.Handler {
+	return authenticate(users, recorder, authOptions{})
+}
+
+// AllowPasswordReset is like Middleware but lets through users whose password
+// must be reset, so they can reach the password change endpoint.
+func AllowPasswordReset(users repository.UserRepository, recorder ImpersonationRecorder) fiber.Handler {
+	return authenticate(users, recorder, authOptions{allowPasswordReset: true})
+}
+
+// Optional is like Middleware but lets anonymous requests through, for public
+// endpoints whose response depends on who is asking.
+func Optional(users repository.UserRepository, recorder ImpersonationRecorder) fiber.Handler {
+	return authenticate(users, recorder, authOptions{optional: true})
+}
+
+type authOptions struct {
+	allowPasswordReset bool
+	optional           bool
+}
+
+func authenticate(users repository.UserRepository, recorder ImpersonationRecorder, opts authOptions) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 
+		if authHeader == "" && opts.optional {
+			return c.Next()
+		}
 		if authHeader == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Authorization header missing",
@@ -27,13 +73,155 @@ func Middleware() fiber.Handler {
 
 		token := tokenParts[1]
 
-		if err := auth.VerifyToken(token); err != nil {
+		claims, err := auth.VerifyToken(token)
+		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Invalid or expired token",
 			})
 		}
 
-		// Token is valid, continue
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		state, err := users.GetUserAuthState(ctx, claims.ID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": "Invalid or expired token",
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to verify session",
+			})
+		}
+
+		if claims.Generation != state.SessionGeneration {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Session has been revoked, please log in again",
+			})
+		}
+		if state.Suspended {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Account suspended",
+			})
+		}
+		if state.PasswordResetRequired && !opts.allowPasswordReset {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Password reset required",
+			})
+		}
+
+		if claims.ImpersonatorID != "" {
+			if status, message := verifyImpersonator(ctx, users, claims); status != 0 {
+				return c.Status(status).JSON(fiber.Map{
+					"error": message,
+				})
+			}
+		}
+
+		// Token is valid, expose the caller to the handlers and continue
+		c.Locals(userIDKey, state.ID)
+		c.Locals(userRoleKey, state.Role)
+
+		if claims.ImpersonatorID == "" {
+			return c.Next()
+		}
+
+		c.Locals(impersonatorKey, claims.ImpersonatorID)
+		c.Set(HeaderImpersonatedBy, claims.ImpersonatorID)
+		err = c.Next()
+		recorder.RecordImpersonatedRequest(c)
+		return err
+	}
+}
+
+// verifyImpersonator checks that the administrator behind an impersonation
+// token may still impersonate. It returns the status and message of the
+// error response, or a zero status when the token is acceptable.
+func verifyImpersonator(ctx context.Context, users repository.UserRepository, claims *auth.Claims) (int, string) {
+	actor, err := users.GetUserAuthState(ctx, claims.ImpersonatorID)
+	if err != nil && err.Error() != "user not found" {
+		return fiber.StatusInternalServerError, "Failed to verify session"
+	}
+	if err != nil || actor.Role != model.RoleAdmin || actor.Suspended ||
+		claims.ImpersonatorGeneration != actor.SessionGeneration {
+		return fiber.StatusUnauthorized, "Impersonation session is no longer valid"
+	}
+	return 0, ""
+}
+
+// CurrentUserID returns the ID of the authenticated user, or an empty string
+// when the request did not pass through Middleware.
+func CurrentUserID(c *fiber.Ctx) string {
+	id, _ := c.Locals(userIDKey).(string)
+	return id
+}
+
+// CurrentUserRole returns the role of the authenticated user, or an empty
+// string when the request did not pass through Middleware.
+func CurrentUserRole(c *fiber.Ctx) string {
+	role, _ := c.Locals(userRoleKey).(string)
+	return role
+}
+
+// ImpersonatorID returns the ID of the administrator impersonating the
+// authenticated user, or an empty string for regular requests.
+func ImpersonatorID(c *fiber.Ctx) string {
+	id, _ := c.Locals(impersonatorKey).(string)
+	return id
+}
+
+// BlockImpersonation rejects the request when it is made with an
+// impersonation token. Use it on sensitive operations such as password
+// changes and account deletion. It must be chained after Middleware.
+func BlockImpersonation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if ImpersonatorID(c) != "" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "This operation is not allowed while impersonating a user",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// RequireAdmin only lets through authenticated administrators. It must be
+// chained after Middleware.
+func RequireAdmin() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if CurrentUserRole(c) != model.RoleAdmin {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Admin access required",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// LimitBody turns down with 413 request bodies larger than limit bytes, and
+// with 411 those of unknown length. The server streams request bodies
+// instead of buffering them, so the check is made on the declared length
+// before anything is read. Requests for which skip returns true are let
+// through: upload routes enforce a limit of their own while reading.
+func LimitBody(limit int, skip func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if skip != nil && skip(c) {
+			return c.Next()
+		}
+		switch n := c.Request().Header.ContentLength(); {
+		case n > limit:
+			// The body is left unread, so it must not be taken for the
+			// next request on the connection.
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": "Request body is too large",
+			})
+		case n == -1:
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusLengthRequired).JSON(fiber.Map{
+				"error": "Content-Length is required",
+			})
+		}
 		return c.Next()
 	}
 }
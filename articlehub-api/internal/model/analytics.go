@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// ArticleVisit is a single view of a published article. Read marks visits
+// where the reader got through the article rather than just opening it.
+type ArticleVisit struct {
+	ArticleID string
+	ViewerID  string
+	Day       time.Time
+	Visitor   []byte
+	Referrer  string
+	Read      bool
+}
+
+type RecordVisitRequest struct {
+	// Referrer is the page that linked to the article, as seen by the
+	// client. The Referer header is used when it is empty.
+	Referrer string `json:"referrer"`
+}
+
+// AuthorStats is an author's audience over a range of days, both ends
+// included.
+type AuthorStats struct {
+	From      string             `json:"from"`
+	To        string             `json:"to"`
+	Views     int64              `json:"views"`
+	Reads     int64              `json:"reads"`
+	Reactions map[string]int64   `json:"reactions"`
+	Daily     []DailyStats       `json:"daily"`
+	Referrers []ReferrerStats    `json:"referrers"`
+	Articles  []ArticleViewStats `json:"articles"`
+}
+
+type DailyStats struct {
+	Date  string `json:"date"`
+	Views int64  `json:"views"`
+	Reads int64  `json:"reads"`
+}
+
+type ReferrerStats struct {
+	Referrer string `json:"referrer"`
+	Views    int64  `json:"views"`
+}
+
+type ArticleViewStats struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Views     int64  `json:"views"`
+	Reads     int64  `json:"reads"`
+	Reactions int64  `json:"reactions"`
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusInReview  = "in_review"
+	ArticleStatusScheduled = "scheduled"
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
+// articleTransition is a status an article may move to next. Moves marked
+// adminOnly are the review step: only an administrator may approve an
+// article for publication.
+type articleTransition struct {
+	to        string
+	adminOnly bool
+}
+
+// articleTransitions lists, for each status, the statuses an article may move
+// to next. Articles are only published or scheduled once reviewed.
+var articleTransitions = map[string][]articleTransition{
+	ArticleStatusDraft: {
+		{to: ArticleStatusInReview},
+		{to: ArticleStatusArchived},
+	},
+	ArticleStatusInReview: {
+		{to: ArticleStatusDraft},
+		{to: ArticleStatusScheduled, adminOnly: true},
+		{to: ArticleStatusPublished, adminOnly: true},
+		{to: ArticleStatusArchived},
+	},
+	ArticleStatusScheduled: {
+		{to: ArticleStatusDraft},
+		{to: ArticleStatusPublished, adminOnly: true},
+		{to: ArticleStatusArchived},
+	},
+	ArticleStatusPublished: {
+		{to: ArticleStatusDraft},
+		{to: ArticleStatusArchived},
+	},
+	ArticleStatusArchived: {
+		{to: ArticleStatusDraft},
+	},
+}
+
+// CanTransition reports whether a user with the given role may move an
+// article in status from to status to.
+func CanTransition(from, to, role string) bool {
+	for _, next := range articleTransitions[from] {
+		if next.to == to {
+			return !next.adminOnly || role == RoleAdmin
+		}
+	}
+	return false
+}
+
+type Article struct {
+	ID          string            `json:"id" db:"id"`
+	Slug        string            `json:"slug" db:"slug"`
+	AuthorID    string            `json:"author_id" db:"author_id"`
+	AuthorName  string            `json:"author_name" db:"-"`
+	Title       string            `json:"title" db:"title"`
+	Body        string            `json:"body" db:"body"`
+	BodyHTML    string            `json:"body_html" db:"body_html"`
+	Status      string            `json:"status" db:"status"`
+	PublishAt   *time.Time        `json:"publish_at" db:"publish_at"`
+	PublishedAt *time.Time        `json:"published_at" db:"published_at"`
+	Tags        []string          `json:"tags" db:"-"`
+	CategoryIDs []string          `json:"category_ids" db:"-"`
+	Reactions   map[string]int    `json:"reactions" db:"-"`
+	Series      *SeriesNavigation `json:"series,omitempty" db:"-"`
+	SEO         *SEOMetadata      `json:"seo,omitempty" db:"-"`
+	Cover       *Media            `json:"cover" db:"-"`
+	ArticleStats
+	Version   int       `json:"version" db:"version"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ArticleStats is computed from the article body whenever it is saved, so
+// that listings can show it without parsing the body again.
+type ArticleStats struct {
+	WordCount int `json:"word_count" db:"word_count"`
+	// ReadingTime is the estimated reading time in minutes.
+	ReadingTime int        `json:"reading_time" db:"reading_time"`
+	TOC         []TOCEntry `json:"toc" db:"toc"`
+}
+
+// TOCEntry is a heading of an article. ID is the anchor of the heading in
+// the rendered body.
+type TOCEntry struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// ArticleTransition records a status change and who made it. ActorID is
+// empty for transitions made by the publishing scheduler.
+type ArticleTransition struct {
+	ID         string    `json:"id" db:"id"`
+	ArticleID  string    `json:"article_id" db:"article_id"`
+	FromStatus string    `json:"from_status" db:"from_status"`
+	ToStatus   string    `json:"to_status" db:"to_status"`
+	ActorID    string    `json:"actor_id,omitempty" db:"actor_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// MaxArticleTags is the number of tags an article may carry.
+const MaxArticleTags = 10
+
+// ArticleFilter narrows down an article listing. Zero values are ignored.
+type ArticleFilter struct {
+	// ViewerID also lets through the viewer's own unpublished articles.
+	ViewerID string
+	AuthorID string
+	// Tags only keeps articles carrying all of them, or any of them when
+	// AnyTag is set.
+	Tags   []string
+	AnyTag bool
+	// CategoryID only keeps articles filed under the category or any of its
+	// descendants.
+	CategoryID string
+	// Limit caps the number of articles returned, 0 for no limit.
+	Limit int
+}
+
+type CreateArticleRequest struct {
+	Title        string   `json:"title" validate:"required,min=1,max=200"`
+	Body         string   `json:"body" validate:"required"`
+	Tags         []string `json:"tags"`
+	CategoryIDs  []string `json:"category_ids"`
+	CoverMediaID string   `json:"cover_media_id"`
+}
+
+// UpdateArticleRequest leaves tags, categories and the cover untouched when
+// they are omitted; send an empty list or cover ID to clear them.
+type UpdateArticleRequest struct {
+	Title        string   `json:"title" validate:"max=200"`
+	Body         string   `json:"body"`
+	Tags         []string `json:"tags"`
+	CategoryIDs  []string `json:"category_ids"`
+	CoverMediaID *string  `json:"cover_media_id"`
+}
+
+type TransitionArticleRequest struct {
+	Status    string     `json:"status" validate:"required"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// ArticleRevision is an immutable snapshot of an article's content, taken on
+// every write. RestoredFrom is set when the revision restored an older one.
+type ArticleRevision struct {
+	ID           string    `json:"id" db:"id"`
+	ArticleID    string    `json:"article_id" db:"article_id"`
+	Number       int       `json:"number" db:"number"`
+	EditorID     string    `json:"editor_id" db:"editor_id"`
+	Title        string    `json:"title" db:"title"`
+	Body         string    `json:"body,omitempty" db:"body"`
+	RestoredFrom *int      `json:"restored_from,omitempty" db:"restored_from"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		user     bool
+		admin    bool
+	}{
+		{ArticleStatusDraft, ArticleStatusInReview, true, true},
+		{ArticleStatusDraft, ArticleStatusScheduled, false, false},
+		{ArticleStatusDraft, ArticleStatusPublished, false, false},
+		{ArticleStatusDraft, ArticleStatusArchived, true, true},
+		{ArticleStatusDraft, ArticleStatusDraft, false, false},
+
+		{ArticleStatusInReview, ArticleStatusDraft, true, true},
+		{ArticleStatusInReview, ArticleStatusScheduled, false, true},
+		{ArticleStatusInReview, ArticleStatusPublished, false, true},
+		{ArticleStatusInReview, ArticleStatusArchived, true, true},
+
+		{ArticleStatusScheduled, ArticleStatusDraft, true, true},
+		{ArticleStatusScheduled, ArticleStatusInReview, false, false},
+		{ArticleStatusScheduled, ArticleStatusPublished, false, true},
+		{ArticleStatusScheduled, ArticleStatusArchived, true, true},
+
+		{ArticleStatusPublished, ArticleStatusDraft, true, true},
+		{ArticleStatusPublished, ArticleStatusInReview, false, false},
+		{ArticleStatusPublished, ArticleStatusScheduled, false, false},
+		{ArticleStatusPublished, ArticleStatusArchived, true, true},
+
+		{ArticleStatusArchived, ArticleStatusDraft, true, true},
+		{ArticleStatusArchived, ArticleStatusPublished, false, false},
+
+		{"unknown", ArticleStatusDraft, false, false},
+		{ArticleStatusDraft, "unknown", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to, RoleUser); got != tt.user {
+			t.Errorf("CanTransition(%q, %q, user) = %v, want %v", tt.from, tt.to, got, tt.user)
+		}
+		if got := CanTransition(tt.from, tt.to, RoleAdmin); got != tt.admin {
+			t.Errorf("CanTransition(%q, %q, admin) = %v, want %v", tt.from, tt.to, got, tt.admin)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	AuditActionUserCreate      = "user.create"
+	AuditActionUserLogin       = "user.login"
+	AuditActionUserLoginFailed = "user.login_failed"
+	AuditActionUserUpdate      = "user.update"
+	AuditActionUserDeactivate  = "user.deactivate"
+	AuditActionUserRestore     = "user.restore"
+	AuditActionPasswordChange  = "user.password_change"
+	AuditActionExportCreate    = "export.create"
+	AuditActionExportDownload  = "export.download"
+
+	AuditActionAdminRoleChange         = "admin.role_change"
+	AuditActionAdminSuspend            = "admin.suspend"
+	AuditActionAdminUnsuspend          = "admin.unsuspend"
+	AuditActionAdminForcePasswordReset = "admin.force_password_reset"
+	AuditActionAdminRevokeSessions     = "admin.revoke_sessions"
+	AuditActionAdminImpersonate        = "admin.impersonate"
+	AuditActionImpersonatedRequest     = "impersonation.request"
+)
+
+// AuditChange is the before and after value of a single changed field.
+type AuditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// AuditEntry is an immutable record of a security relevant action.
+type AuditEntry struct {
+	ID      string `json:"id" db:"id"`
+	ActorID string `json:"actor_id,omitempty" db:"actor_id"`
+	// SubjectID is the impersonated user when ActorID acted on their behalf.
+	SubjectID  string                 `json:"subject_id,omitempty" db:"subject_id"`
+	Action     string                 `json:"action" db:"action"`
+	TargetType string                 `json:"target_type,omitempty" db:"target_type"`
+	TargetID   string                 `json:"target_id,omitempty" db:"target_id"`
+	IP         string                 `json:"ip" db:"ip"`
+	UserAgent  string                 `json:"user_agent" db:"user_agent"`
+	Changes    map[string]AuditChange `json:"changes,omitempty" db:"changes"`
+	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter narrows down an audit log query. Zero values are ignored.
+type AuditFilter struct {
+	ActorID    string
+	SubjectID  string
+	Action     string
+	TargetType string
+	TargetID   string
+	IP         string
+	From       time.Time
+	To         time.Time
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+)
+
+// Comment is a node of an article's discussion. Top-level comments have no
+// parent and a depth of 0; RootID points at the top-level comment of the
+// thread. Deleted comments are kept as placeholders without body or author.
+// ReplyCount is the number of direct replies, which may be more than are
+// loaded in Replies.
+type Comment struct {
+	ID         string         `json:"id" db:"id"`
+	ArticleID  string         `json:"article_id" db:"article_id"`
+	AuthorID   string         `json:"author_id,omitempty" db:"author_id"`
+	ParentID   *string        `json:"parent_id" db:"parent_id"`
+	RootID     string         `json:"root_id" db:"root_id"`
+	Depth      int            `json:"depth" db:"depth"`
+	Body       string         `json:"body" db:"body"`
+	BodyHTML   string         `json:"body_html" db:"body_html"`
+	Edited     bool           `json:"edited" db:"-"`
+	EditedAt   *time.Time     `json:"edited_at,omitempty" db:"edited_at"`
+	Deleted    bool           `json:"deleted" db:"-"`
+	DeletedAt  *time.Time     `json:"-" db:"deleted_at"`
+	Reactions  map[string]int `json:"reactions" db:"-"`
+	Replies    []*Comment     `json:"replies,omitempty"`
+	ReplyCount int            `json:"reply_count" db:"-"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// CommentTree nests a flat list of comments under their parents and returns
+// the top-level ones. Replies keep the order of the input list.
+func CommentTree(comments []Comment) []*Comment {
+	nodes := make(map[string]*Comment, len(comments))
+	for i := range comments {
+		nodes[comments[i].ID] = &comments[i]
+	}
+
+	roots := []*Comment{}
+	for i := range comments {
+		node := &comments[i]
+		if node.ParentID != nil {
+			if parent, ok := nodes[*node.ParentID]; ok {
+				parent.Replies = append(parent.Replies, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+type CreateCommentRequest struct {
+	Body     string  `json:"body" validate:"required,max=10000"`
+	ParentID *string `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	ExportStatusPending    = "pending"
+	ExportStatusProcessing = "processing"
+	ExportStatusReady      = "ready"
+	ExportStatusFailed     = "failed"
+)
+
+// DataExport is an asynchronous request to export all data held about a user.
+type DataExport struct {
+	ID          string     `json:"id" db:"id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	Status      string     `json:"status" db:"status"`
+	FilePath    string     `json:"-" db:"file_path"`
+	Error       string     `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+}
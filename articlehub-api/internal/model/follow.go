@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// FollowUser is an entry of a follower or following list.
+type FollowUser struct {
+	ID         string    `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	AvatarURL  string    `json:"avatar_url" db:"avatar_url"`
+	FollowedAt time.Time `json:"followed_at" db:"created_at"`
+}
+
+// FollowCounts tells how many users follow a user and how many they follow.
+type FollowCounts struct {
+	Followers int `json:"followers"`
+	Following int `json:"following"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Media is an image in a user's library. Besides the original, it is stored
+// at every configured width smaller than its own, for responsive images.
+type Media struct {
+	ID          string         `json:"id" db:"id"`
+	OwnerID     string         `json:"owner_id" db:"owner_id"`
+	URL         string         `json:"url" db:"url"`
+	ContentType string         `json:"content_type" db:"content_type"`
+	Width       int            `json:"width" db:"width"`
+	Height      int            `json:"height" db:"height"`
+	Size        int64          `json:"size" db:"size"`
+	Alt         string         `json:"alt" db:"alt"`
+	Variants    []MediaVariant `json:"variants" db:"variants"`
+	// Keys are the storage keys of the original and its variants.
+	Keys      []string  `json:"-" db:"storage_keys"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type MediaVariant struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url"`
+}
+
+// SrcSet lists the variants and the original in the format of the img
+// srcset attribute.
+func (m *Media) SrcSet() string {
+	candidates := make([]string, 0, len(m.Variants)+1)
+	for _, v := range m.Variants {
+		candidates = append(candidates, v.URL+" "+strconv.Itoa(v.Width)+"w")
+	}
+	candidates = append(candidates, m.URL+" "+strconv.Itoa(m.Width)+"w")
+	return strings.Join(candidates, ", ")
+}
+
+// MediaReference is the Markdown image destination that refers to a media
+// item, as in ![alt](media:ID).
+const MediaReference = "media:"
+
+// MaxMediaAltLength caps the alt text of a media item, in characters.
+const MaxMediaAltLength = 1000
+
+type UpdateMediaRequest struct {
+	Alt string `json:"alt" validate:"max=1000"`
+}
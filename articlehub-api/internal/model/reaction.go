@@ -0,0 +1,29 @@
+package model
+
+const (
+	ReactionTargetArticle = "article"
+	ReactionTargetComment = "comment"
+)
+
+// Reactions maps every reaction readers may use to the emoji shown for it.
+var Reactions = map[string]string{
+	"like":       "👍",
+	"clap":       "👏",
+	"love":       "❤️",
+	"laugh":      "😂",
+	"insightful": "💡",
+	"sad":        "😢",
+}
+
+// ValidReaction reports whether reaction is one of Reactions.
+func ValidReaction(reaction string) bool {
+	_, ok := Reactions[reaction]
+	return ok
+}
+
+// ReactionSummary is the state of a target's reactions as seen by a reader.
+// Reaction is the reader's own reaction, empty when they have none.
+type ReactionSummary struct {
+	Counts   map[string]int `json:"counts"`
+	Reaction string         `json:"reaction"`
+}
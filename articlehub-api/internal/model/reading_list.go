@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	ListVisibilityPrivate  = "private"
+	ListVisibilityUnlisted = "unlisted"
+	ListVisibilityPublic   = "public"
+)
+
+// ValidListVisibility reports whether visibility is a known reading list
+// visibility.
+func ValidListVisibility(visibility string) bool {
+	switch visibility {
+	case ListVisibilityPrivate, ListVisibilityUnlisted, ListVisibilityPublic:
+		return true
+	}
+	return false
+}
+
+type Bookmark struct {
+	ArticleID string    `json:"article_id" db:"article_id"`
+	Note      string    `json:"note" db:"note"`
+	AddedAt   time.Time `json:"added_at" db:"added_at"`
+	Article   *Article  `json:"article,omitempty"`
+}
+
+// ReadingList is a named, ordered collection of articles. ShareToken builds
+// the list's shareable URL and is only exposed when the list is not private.
+type ReadingList struct {
+	ID          string            `json:"id" db:"id"`
+	OwnerID     string            `json:"owner_id" db:"owner_id"`
+	Name        string            `json:"name" db:"name"`
+	Description string            `json:"description" db:"description"`
+	Visibility  string            `json:"visibility" db:"visibility"`
+	ShareToken  string            `json:"share_token,omitempty" db:"share_token"`
+	ItemCount   int               `json:"item_count" db:"item_count"`
+	Items       []ReadingListItem `json:"items,omitempty"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+type ReadingListItem struct {
+	ArticleID string    `json:"article_id" db:"article_id"`
+	Position  int       `json:"position" db:"position"`
+	Note      string    `json:"note" db:"note"`
+	AddedAt   time.Time `json:"added_at" db:"added_at"`
+	Article   *Article  `json:"article,omitempty"`
+}
+
+type BookmarkRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+type ReadingListRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+	Visibility  string `json:"visibility"`
+}
+
+type ReadingListItemRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// ReorderReadingListRequest lists every article of the reading list in its
+// new order.
+type ReorderReadingListRequest struct {
+	ArticleIDs []string `json:"article_ids" validate:"required"`
+}
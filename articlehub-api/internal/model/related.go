@@ -0,0 +1,25 @@
+package model
+
+// ArticleDocument is the part of a published article recommendations are
+// computed from. Readers are the users other than the author who engaged
+// with it.
+type ArticleDocument struct {
+	ID      string
+	Title   string
+	Body    string
+	Tags    []string
+	Readers []string
+}
+
+// RelatedArticle is a recommendation with its similarity to the article it
+// was recommended for, between 0 and 1.
+type RelatedArticle struct {
+	Article
+	Score float64 `json:"related_score"`
+}
+
+type Recommendation struct {
+	ArticleID string
+	RelatedID string
+	Score     float64
+}
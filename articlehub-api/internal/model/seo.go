@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// SEOMetadata is what a page rendering an article needs for its <head>:
+// canonical URL, Open Graph and Twitter card tags and a JSON-LD document.
+type SEOMetadata struct {
+	CanonicalURL string         `json:"canonical_url"`
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	Robots       string         `json:"robots"`
+	OpenGraph    []MetaTag      `json:"open_graph"`
+	TwitterCard  []MetaTag      `json:"twitter_card"`
+	JSONLD       map[string]any `json:"json_ld"`
+}
+
+// MetaTag is one <meta> element. Open Graph tags use Name as the property
+// attribute, Twitter card tags as the name attribute.
+type MetaTag struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// SitemapEntry is a published article as listed in the sitemap.
+type SitemapEntry struct {
+	Slug      string
+	UpdatedAt time.Time
+}
+
+// SitemapPage describes one file of a sitemap split across several.
+type SitemapPage struct {
+	Number       int
+	LastModified time.Time
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+)
+
+// Series is an ordered collection of articles by one author, such as a
+// multi-part tutorial.
+type Series struct {
+	ID          string       `json:"id" db:"id"`
+	AuthorID    string       `json:"author_id" db:"author_id"`
+	Title       string       `json:"title" db:"title"`
+	Description string       `json:"description" db:"description"`
+	PartCount   int          `json:"part_count" db:"part_count"`
+	Parts       []SeriesPart `json:"parts,omitempty"`
+	Version     int          `json:"version" db:"version"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+type SeriesPart struct {
+	ArticleID   string     `json:"article_id" db:"article_id"`
+	Title       string     `json:"title" db:"title"`
+	Status      string     `json:"status" db:"status"`
+	PublishedAt *time.Time `json:"published_at" db:"published_at"`
+	Position    int        `json:"position" db:"position"`
+}
+
+// SeriesNavigation places an article within its series, counting only the
+// parts visible to the reader.
+type SeriesNavigation struct {
+	ID       string      `json:"id"`
+	Title    string      `json:"title"`
+	Position int         `json:"position"`
+	Total    int         `json:"total"`
+	Previous *SeriesPart `json:"previous"`
+	Next     *SeriesPart `json:"next"`
+}
+
+type SeriesRequest struct {
+	Title       string `json:"title" validate:"required,max=200"`
+	Description string `json:"description" validate:"max=2000"`
+}
+
+// ReorderSeriesRequest lists every article of the series in its new order.
+type ReorderSeriesRequest struct {
+	ArticleIDs []string `json:"article_ids" validate:"required"`
+}
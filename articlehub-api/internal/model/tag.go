@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+)
+
+type Tag struct {
+	ID           string `json:"id" db:"id"`
+	Name         string `json:"name" db:"name"`
+	ArticleCount int    `json:"article_count" db:"article_count"`
+}
+
+// TagAlias maps an alternative spelling to a canonical tag.
+type TagAlias struct {
+	Alias     string    `json:"alias" db:"alias"`
+	Tag       string    `json:"tag" db:"tag"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Category is a node of the administrator curated category tree.
+// ArticleCount only counts published articles filed directly under it.
+type Category struct {
+	ID           string      `json:"id" db:"id"`
+	ParentID     *string     `json:"parent_id" db:"parent_id"`
+	Name         string      `json:"name" db:"name"`
+	Description  string      `json:"description" db:"description"`
+	ArticleCount int         `json:"article_count" db:"article_count"`
+	Children     []*Category `json:"children,omitempty"`
+	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// CategoryTree arranges a flat list of categories into trees and returns
+// the roots. Children keep the order of the input list.
+func CategoryTree(categories []Category) []*Category {
+	nodes := make(map[string]*Category, len(categories))
+	for i := range categories {
+		nodes[categories[i].ID] = &categories[i]
+	}
+
+	roots := []*Category{}
+	for i := range categories {
+		node := &categories[i]
+		if node.ParentID != nil {
+			if parent, ok := nodes[*node.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+type CreateTagAliasRequest struct {
+	Alias string `json:"alias" validate:"required"`
+	Tag   string `json:"tag" validate:"required"`
+}
+
+type CategoryRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	ParentID    *string `json:"parent_id"`
+	Description string  `json:"description"`
+}
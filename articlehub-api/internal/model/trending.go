@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// TrendingWindow is a period articles are ranked over. Activity counts for
+// less the older it is, halving every HalfLife, and not at all past Length.
+type TrendingWindow struct {
+	Name     string
+	Length   time.Duration
+	HalfLife time.Duration
+}
+
+var TrendingWindows = map[string]TrendingWindow{
+	"day":   {Name: "day", Length: 24 * time.Hour, HalfLife: 6 * time.Hour},
+	"week":  {Name: "week", Length: 7 * 24 * time.Hour, HalfLife: 2 * 24 * time.Hour},
+	"month": {Name: "month", Length: 30 * 24 * time.Hour, HalfLife: 7 * 24 * time.Hour},
+}
+
+type TrendingFilter struct {
+	// Tag restricts the ranking to articles with a tag, empty for all.
+	Tag    string
+	Limit  int
+	Offset int
+}
+
+// TrendingArticle is an article with its current score in a window.
+type TrendingArticle struct {
+	Article
+	Score float64 `json:"trending_score"`
+}
@@ -5,13 +5,85 @@ import (
 )
 
 type User struct {
-	ID        string    `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password"`
-	AvatarURL string    `json:"avatar_url" db:"avatar_url"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID        string     `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	Slug      string     `json:"slug" db:"slug"`
+	Email     string     `json:"email" db:"email"`
+	Password  string     `json:"-" db:"password"`
+	AvatarURL string     `json:"avatar_url" db:"avatar_url"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
+	Version   int        `json:"version" db:"version"`
+
+	SuspendedAt           *time.Time `json:"-" db:"suspended_at"`
+	PasswordResetRequired bool       `json:"-" db:"password_reset_required"`
+	SessionGeneration     int        `json:"-" db:"session_generation"`
+}
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// ValidRole reports whether role is one of the known user roles.
+func ValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
+}
+
+// UserAccount is the administrator's view of a user, including the private
+// account state hidden from the public user endpoints.
+type UserAccount struct {
+	ID                    string     `json:"id"`
+	Name                  string     `json:"name"`
+	Email                 string     `json:"email"`
+	AvatarURL             string     `json:"avatar_url"`
+	Role                  string     `json:"role"`
+	SuspendedAt           *time.Time `json:"suspended_at"`
+	SuspensionReason      string     `json:"suspension_reason,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	TokensValidAfter      *time.Time `json:"tokens_valid_after"`
+	DeactivatedAt         *time.Time `json:"deactivated_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// UserAuthState is the account state checked on every authenticated request.
+type UserAuthState struct {
+	ID                    string
+	Role                  string
+	Suspended             bool
+	PasswordResetRequired bool
+	// SessionGeneration must match the generation of the tokens accepted.
+	SessionGeneration int
+}
+
+// UserFilter narrows down an administrator's user search. Zero values are
+// ignored.
+type UserFilter struct {
+	Query  string
+	Role   string
+	Status string
+	Limit  int
+	Offset int
+}
+
+// UserStats summarises a user's activity for administrators.
+type UserStats struct {
+	UserID       string           `json:"user_id"`
+	LastLoginAt  *time.Time       `json:"last_login_at"`
+	Logins       int64            `json:"logins"`
+	FailedLogins int64            `json:"failed_logins"`
+	DataExports  int64            `json:"data_exports"`
+	Actions      map[string]int64 `json:"actions"`
+}
+
+// PurgedObject is a stored object of a purged account waiting to be removed
+// from storage. Media files are known by their key, avatars by their URL.
+type PurgedObject struct {
+	ID  int64
+	Key string
+	URL string
 }
 
 type CreateUserRequest struct {
@@ -30,3 +102,20 @@ type LoginRequest struct {
 	Email    string `json:"email" validate:"email"`
 	Password string `json:"password" validate:"required,min=6,max=100"`
 }
+
+type DeactivateUserRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6,max=100"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason"`
+}
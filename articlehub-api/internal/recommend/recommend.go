@@ -0,0 +1,226 @@
+// Package recommend finds the articles most similar to each article of a
+// corpus. Similarity blends three signals, each a cosine similarity between
+// sparse vectors: the TF-IDF weights of the article text, its tags, and the
+// readers who engaged with it.
+package recommend
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Document is an article as seen by the recommender.
+type Document struct {
+	ID    string
+	Title string
+	Body  string
+	Tags  []string
+	// Readers are the users who bookmarked, listed, reacted to or
+	// commented on the article.
+	Readers []string
+}
+
+type Neighbour struct {
+	ID    string
+	Score float64
+}
+
+// Weights sets how much each signal contributes to the blended score.
+type Weights struct {
+	Text    float64
+	Tags    float64
+	Readers float64
+}
+
+var DefaultWeights = Weights{Text: 0.5, Tags: 0.3, Readers: 0.2}
+
+const (
+	// maxTerms is the number of highest weighted terms kept per document.
+	maxTerms = 100
+	// maxPostings skips terms, tags and readers shared by more documents
+	// than this: they say little about similarity and would make the
+	// comparison quadratic in the corpus size.
+	maxPostings = 1000
+	// minScore is the lowest blended score worth recommending.
+	minScore = 0.05
+)
+
+// Neighbours returns up to n neighbours for every document, most similar
+// first. Documents sharing nothing with any other have none.
+func Neighbours(docs []Document, n int, w Weights) map[string][]Neighbour {
+	scores := make([]map[int]float64, len(docs))
+	for i := range scores {
+		scores[i] = map[int]float64{}
+	}
+
+	text := textVectors(docs)
+	tags := make([]map[string]float64, len(docs))
+	readers := make([]map[string]float64, len(docs))
+	for i, doc := range docs {
+		tags[i] = setVector(doc.Tags)
+		readers[i] = setVector(doc.Readers)
+	}
+	accumulate(scores, text, w.Text)
+	accumulate(scores, tags, w.Tags)
+	accumulate(scores, readers, w.Readers)
+
+	neighbours := make(map[string][]Neighbour, len(docs))
+	for i, doc := range docs {
+		var candidates []Neighbour
+		for j, score := range scores[i] {
+			if score >= minScore {
+				candidates = append(candidates, Neighbour{ID: docs[j].ID, Score: score})
+			}
+		}
+		sort.Slice(candidates, func(a, b int) bool {
+			if candidates[a].Score != candidates[b].Score {
+				return candidates[a].Score > candidates[b].Score
+			}
+			return candidates[a].ID < candidates[b].ID
+		})
+		if len(candidates) > n {
+			candidates = candidates[:n]
+		}
+		if len(candidates) > 0 {
+			neighbours[doc.ID] = candidates
+		}
+	}
+	return neighbours
+}
+
+// accumulate adds weight times the cosine similarity of every pair of unit
+// vectors to their scores. Only pairs sharing a dimension are visited.
+func accumulate(scores []map[int]float64, vectors []map[string]float64, weight float64) {
+	if weight == 0 {
+		return
+	}
+	type posting struct {
+		doc   int
+		value float64
+	}
+	postings := map[string][]posting{}
+	for i, vector := range vectors {
+		for key, value := range vector {
+			postings[key] = append(postings[key], posting{i, value})
+		}
+	}
+	for _, list := range postings {
+		if len(list) < 2 || len(list) > maxPostings {
+			continue
+		}
+		for a := 0; a < len(list); a++ {
+			for b := a + 1; b < len(list); b++ {
+				s := weight * list[a].value * list[b].value
+				scores[list[a].doc][list[b].doc] += s
+				scores[list[b].doc][list[a].doc] += s
+			}
+		}
+	}
+}
+
+// setVector is the unit vector with an equal component for every member.
+func setVector(members []string) map[string]float64 {
+	vector := map[string]float64{}
+	for _, m := range members {
+		vector[m] = 1
+	}
+	for m := range vector {
+		vector[m] = 1 / math.Sqrt(float64(len(vector)))
+	}
+	return vector
+}
+
+// textVectors weighs the terms of every document by TF-IDF, keeps the
+// maxTerms heaviest and normalises the result to a unit vector. Title terms
+// count three times.
+func textVectors(docs []Document) []map[string]float64 {
+	counts := make([]map[string]int, len(docs))
+	df := map[string]int{}
+	for i, doc := range docs {
+		counts[i] = map[string]int{}
+		for _, term := range terms(doc.Title) {
+			counts[i][term] += 3
+		}
+		for _, term := range terms(doc.Body) {
+			counts[i][term]++
+		}
+		for term := range counts[i] {
+			df[term]++
+		}
+	}
+
+	vectors := make([]map[string]float64, len(docs))
+	for i := range docs {
+		type weighted struct {
+			term   string
+			weight float64
+		}
+		var ws []weighted
+		for term, count := range counts[i] {
+			// Terms in a single document cannot relate it to another.
+			if df[term] < 2 {
+				continue
+			}
+			idf := math.Log(float64(len(docs)) / float64(df[term]))
+			ws = append(ws, weighted{term, (1 + math.Log(float64(count))) * idf})
+		}
+		sort.Slice(ws, func(a, b int) bool {
+			if ws[a].weight != ws[b].weight {
+				return ws[a].weight > ws[b].weight
+			}
+			return ws[a].term < ws[b].term
+		})
+		if len(ws) > maxTerms {
+			ws = ws[:maxTerms]
+		}
+
+		var norm float64
+		for _, w := range ws {
+			norm += w.weight * w.weight
+		}
+		vectors[i] = map[string]float64{}
+		if norm == 0 {
+			continue
+		}
+		norm = math.Sqrt(norm)
+		for _, w := range ws {
+			vectors[i][w.term] = w.weight / norm
+		}
+	}
+	return vectors
+}
+
+// terms splits text into lower case words, leaving out stop words, numbers
+// and single characters.
+func terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) < 2 || stopWords[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		out = append(out, word)
+	}
+	return out
+}
+
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		a about above after again against all am an and any are as at be because been before being below
+		between both but by can could did do does doing down during each few for from further had has have
+		having he her here hers herself him himself his how if in into is it its itself just let me more
+		most my myself no nor not now of off on once only or other our ours ourselves out over own same she
+		should so some such than that the their theirs them themselves then there these they this those
+		through to too under until up very was we were what when where which while who whom why will with
+		would you your yours yourself yourselves also get got like make one two use used using way well
+		http https www com org html png jpg gif
+	`) {
+		stopWords[word] = true
+	}
+}
@@ -0,0 +1,207 @@
+package recommend
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{}},
+		{"Go Concurrency Patterns", []string{"go", "concurrency", "patterns"}},
+		{"The state of the art", []string{"state", "art"}},
+		{"a b c x y", []string{}},
+		{"Released in 2024, version 1.21", []string{"released", "version"}},
+		{"go1 and http2", []string{"go1", "http2"}},
+		{"see https://www.example.com/page.html", []string{"see", "example", "page"}},
+		{"Ação e reação", []string{"ação", "reação"}},
+		{"日本語 記事", []string{"日本語", "記事"}},
+	}
+
+	for _, tt := range tests {
+		if got := terms(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("terms(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSetVector(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []string
+		want    map[string]float64
+	}{
+		{"empty", nil, map[string]float64{}},
+		{"single", []string{"go"}, map[string]float64{"go": 1}},
+		{"duplicates", []string{"go", "go", "rust", "go"}, map[string]float64{"go": 1 / math.Sqrt2, "rust": 1 / math.Sqrt2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := setVector(tt.members)
+			if len(got) != len(tt.want) {
+				t.Fatalf("setVector(%q) = %v, want %v", tt.members, got, tt.want)
+			}
+			for key, value := range tt.want {
+				if !approx(got[key], value) {
+					t.Errorf("setVector(%q)[%q] = %v, want %v", tt.members, key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	tagsOnly := Weights{Tags: 1}
+	readersOnly := Weights{Readers: 1}
+
+	tests := []struct {
+		name    string
+		docs    []Document
+		n       int
+		weights Weights
+		want    map[string][]Neighbour
+	}{
+		{
+			name:    "empty corpus",
+			n:       5,
+			weights: DefaultWeights,
+			want:    map[string][]Neighbour{},
+		},
+		{
+			name: "nothing shared",
+			docs: []Document{
+				{ID: "a", Tags: []string{"go"}},
+				{ID: "b", Tags: []string{"rust"}},
+			},
+			n:       5,
+			weights: tagsOnly,
+			want:    map[string][]Neighbour{},
+		},
+		{
+			name: "tags",
+			docs: []Document{
+				{ID: "a", Tags: []string{"go", "web"}},
+				{ID: "b", Tags: []string{"go", "web"}},
+				{ID: "c", Tags: []string{"go", "cli"}},
+				{ID: "d", Tags: []string{"rust"}},
+			},
+			n:       5,
+			weights: tagsOnly,
+			want: map[string][]Neighbour{
+				"a": {{"b", 1}, {"c", 0.5}},
+				"b": {{"a", 1}, {"c", 0.5}},
+				"c": {{"a", 0.5}, {"b", 0.5}},
+			},
+		},
+		{
+			name: "limited to n",
+			docs: []Document{
+				{ID: "a", Tags: []string{"go", "web"}},
+				{ID: "b", Tags: []string{"go", "web"}},
+				{ID: "c", Tags: []string{"go", "cli"}},
+			},
+			n:       1,
+			weights: tagsOnly,
+			want: map[string][]Neighbour{
+				"a": {{"b", 1}},
+				"b": {{"a", 1}},
+				"c": {{"a", 0.5}},
+			},
+		},
+		{
+			name: "readers",
+			docs: []Document{
+				{ID: "a", Readers: []string{"u1", "u2", "u3", "u4"}},
+				{ID: "b", Readers: []string{"u1", "u2", "u3", "u4"}},
+				{ID: "c", Readers: []string{"u4", "u5", "u6", "u7"}},
+			},
+			n:       5,
+			weights: readersOnly,
+			want: map[string][]Neighbour{
+				"a": {{"b", 1}, {"c", 0.25}},
+				"b": {{"a", 1}, {"c", 0.25}},
+				"c": {{"a", 0.25}, {"b", 0.25}},
+			},
+		},
+		{
+			name: "below minimum score",
+			docs: []Document{
+				{ID: "a", Tags: []string{"go"}},
+				{ID: "b", Tags: []string{"go"}},
+			},
+			n:       5,
+			weights: Weights{Tags: minScore / 2},
+			want:    map[string][]Neighbour{},
+		},
+		{
+			name: "weights blend",
+			docs: []Document{
+				{ID: "a", Tags: []string{"go"}, Readers: []string{"u1"}},
+				{ID: "b", Tags: []string{"go"}, Readers: []string{"u2"}},
+			},
+			n:       5,
+			weights: Weights{Tags: 0.3, Readers: 0.2},
+			want: map[string][]Neighbour{
+				"a": {{"b", 0.3}},
+				"b": {{"a", 0.3}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Neighbours(tt.docs, tt.n, tt.weights)
+			if !equalNeighbours(got, tt.want) {
+				t.Errorf("Neighbours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeighboursText(t *testing.T) {
+	docs := []Document{
+		{ID: "goroutines", Title: "Goroutines and channels", Body: "Channels connect goroutines. Buffered channels decouple goroutines."},
+		{ID: "channels", Title: "Understanding channels", Body: "Unbuffered channels synchronise goroutines; buffered channels queue values."},
+		{ID: "sourdough", Title: "Baking sourdough", Body: "Feed the starter, then knead the dough and let it rise overnight."},
+		{ID: "bread", Title: "Bread at home", Body: "A starter and patience: knead the dough, let it rise, bake."},
+	}
+
+	got := Neighbours(docs, 5, Weights{Text: 1})
+	for id, want := range map[string]string{
+		"goroutines": "channels",
+		"channels":   "goroutines",
+		"sourdough":  "bread",
+		"bread":      "sourdough",
+	} {
+		if len(got[id]) != 1 || got[id][0].ID != want {
+			t.Errorf("Neighbours()[%q] = %v, want only %q", id, got[id], want)
+		}
+	}
+}
+
+func equalNeighbours(a, b map[string][]Neighbour) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, as := range a {
+		bs, ok := b[id]
+		if !ok || len(as) != len(bs) {
+			return false
+		}
+		for i := range as {
+			if as[i].ID != bs[i].ID || !approx(as[i].Score, bs[i].Score) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"articlehub-api/internal/model"
+)
+
+// maxReferrers caps the referrers listed in an author's stats.
+const maxReferrers = 20
+
+// dailyStats and dailyReferrers combine the rolled up counters with the raw
+// visits not rolled up yet, i.e. those of the current day.
+const (
+	dailyStats = `(
+		SELECT article_id, day, views, reads FROM article_daily_stats
+		UNION ALL
+		SELECT article_id, day, COUNT(*), COUNT(*) FILTER (WHERE read) FROM article_visits GROUP BY article_id, day
+	)`
+	dailyReferrers = `(
+		SELECT article_id, day, referrer, views FROM article_daily_referrers
+		UNION ALL
+		SELECT article_id, day, referrer, COUNT(*) FROM article_visits WHERE referrer <> '' GROUP BY article_id, day, referrer
+	)`
+)
+
+type AnalyticsRepository interface {
+	DailySalt(ctx context.Context, day time.Time) ([]byte, error)
+	RecordVisit(ctx context.Context, visit *model.ArticleVisit) error
+	Rollup(ctx context.Context, today time.Time) (int64, error)
+	GetAuthorStats(ctx context.Context, authorID string, from, to time.Time) (*model.AuthorStats, error)
+}
+
+type analyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// DailySalt returns the salt visitor IDs are hashed with on day, creating it
+// the first time it is asked for. Every instance agrees on the salt, so a
+// visitor is counted once whichever instance serves them.
+func (r *analyticsRepository) DailySalt(ctx context.Context, day time.Time) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO analytics_salts (day, salt) VALUES ($1, $2) ON CONFLICT (day) DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, day, salt); err != nil {
+		return nil, fmt.Errorf("failed to create salt: %w", err)
+	}
+	if err := r.db.QueryRowContext(ctx, `SELECT salt FROM analytics_salts WHERE day = $1`, day).Scan(&salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// RecordVisit counts a view of a published article, at most once per visitor
+// and day; a later visit marked as read upgrades the first one. Authors
+// viewing their own articles and articles that are not published are
+// silently ignored.
+func (r *analyticsRepository) RecordVisit(ctx context.Context, visit *model.ArticleVisit) error {
+	query := `INSERT INTO article_visits (article_id, day, visitor, referrer, read)
+		SELECT id, $2, $3, $4, $5 FROM articles
+		WHERE id = $1 AND status = 'published' AND author_id IS DISTINCT FROM NULLIF($6, '')::uuid
+		ON CONFLICT (article_id, day, visitor) DO UPDATE SET read = TRUE
+		WHERE EXCLUDED.read AND NOT article_visits.read`
+	_, err := r.db.ExecContext(ctx, query, visit.ArticleID, visit.Day, visit.Visitor, visit.Referrer, visit.Read, visit.ViewerID)
+	if err != nil {
+		return fmt.Errorf("failed to record visit: %w", err)
+	}
+	return nil
+}
+
+// Rollup moves the visits of the days before today into the daily counters
+// and deletes them along with their salts, leaving nothing that could
+// identify a visitor. It returns the number of visits rolled up.
+//
+// Counting and deleting happen in one statement, so they see the same
+// visits; one still being recorded for yesterday is added by the next
+// rollup.
+func (r *analyticsRepository) Rollup(ctx context.Context, today time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `WITH totals AS (
+			INSERT INTO article_daily_stats (article_id, day, views, reads)
+			SELECT article_id, day, COUNT(*), COUNT(*) FILTER (WHERE read) FROM article_visits
+			WHERE day < $1 GROUP BY article_id, day
+			ON CONFLICT (article_id, day) DO UPDATE SET
+				views = article_daily_stats.views + EXCLUDED.views,
+				reads = article_daily_stats.reads + EXCLUDED.reads
+		), referrers AS (
+			INSERT INTO article_daily_referrers (article_id, day, referrer, views)
+			SELECT article_id, day, referrer, COUNT(*) FROM article_visits
+			WHERE day < $1 AND referrer <> '' GROUP BY article_id, day, referrer
+			ON CONFLICT (article_id, day, referrer) DO UPDATE SET views = article_daily_referrers.views + EXCLUDED.views
+		), purged AS (
+			DELETE FROM article_visits WHERE day < $1 RETURNING 1
+		)
+		SELECT COUNT(*) FROM purged`
+	var purged int64
+	if err := tx.QueryRowContext(ctx, query, today).Scan(&purged); err != nil {
+		return 0, fmt.Errorf("failed to roll up visits: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM analytics_salts WHERE day < $1`, today); err != nil {
+		return 0, fmt.Errorf("failed to delete salts: %w", err)
+	}
+	return purged, tx.Commit()
+}
+
+// GetAuthorStats aggregates the views, reads, referrers and reactions of an
+// author's articles from the first to the last day given.
+func (r *analyticsRepository) GetAuthorStats(ctx context.Context, authorID string, from, to time.Time) (*model.AuthorStats, error) {
+	stats := &model.AuthorStats{
+		From:      from.Format(time.DateOnly),
+		To:        to.Format(time.DateOnly),
+		Reactions: map[string]int64{},
+		Daily:     []model.DailyStats{},
+		Referrers: []model.ReferrerStats{},
+		Articles:  []model.ArticleViewStats{},
+	}
+	// Reactions carry a timestamp rather than a day.
+	end := to.AddDate(0, 0, 1)
+
+	query := `SELECT s.day, SUM(s.views), SUM(s.reads) FROM ` + dailyStats + ` s
+		JOIN articles a ON a.id = s.article_id
+		WHERE a.author_id = $1 AND s.day BETWEEN $2 AND $3
+		GROUP BY s.day`
+	rows, err := r.db.QueryContext(ctx, query, authorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := map[string]model.DailyStats{}
+	for rows.Next() {
+		var day time.Time
+		var daily model.DailyStats
+		if err := rows.Scan(&day, &daily.Views, &daily.Reads); err != nil {
+			return nil, err
+		}
+		days[day.Format(time.DateOnly)] = daily
+		stats.Views += daily.Views
+		stats.Reads += daily.Reads
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Days without views are listed too, so clients can chart the range as is.
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		daily := days[day.Format(time.DateOnly)]
+		daily.Date = day.Format(time.DateOnly)
+		stats.Daily = append(stats.Daily, daily)
+	}
+
+	query = `SELECT s.referrer, SUM(s.views) AS views FROM ` + dailyReferrers + ` s
+		JOIN articles a ON a.id = s.article_id
+		WHERE a.author_id = $1 AND s.day BETWEEN $2 AND $3
+		GROUP BY s.referrer ORDER BY views DESC, s.referrer LIMIT $4`
+	rows, err = r.db.QueryContext(ctx, query, authorID, from, to, maxReferrers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var referrer model.ReferrerStats
+		if err := rows.Scan(&referrer.Referrer, &referrer.Views); err != nil {
+			return nil, err
+		}
+		stats.Referrers = append(stats.Referrers, referrer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT r.reaction, COUNT(*) FROM article_reactions r
+		JOIN articles a ON a.id = r.article_id
+		WHERE a.author_id = $1 AND r.created_at >= $2 AND r.created_at < $3
+		GROUP BY r.reaction`
+	rows, err = r.db.QueryContext(ctx, query, authorID, from, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reaction string
+		var count int64
+		if err := rows.Scan(&reaction, &count); err != nil {
+			return nil, err
+		}
+		stats.Reactions[reaction] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Articles that are no longer published still show up for the range
+	// they were read in.
+	query = `SELECT a.id, a.title, a.slug, COALESCE(v.views, 0), COALESCE(v.reads, 0), COALESCE(r.reactions, 0)
+		FROM articles a
+		LEFT JOIN (
+			SELECT article_id, SUM(views) AS views, SUM(reads) AS reads FROM ` + dailyStats + ` s
+			WHERE day BETWEEN $2 AND $3 GROUP BY article_id
+		) v ON v.article_id = a.id
+		LEFT JOIN (
+			SELECT article_id, COUNT(*) AS reactions FROM article_reactions
+			WHERE created_at >= $2 AND created_at < $4 GROUP BY article_id
+		) r ON r.article_id = a.id
+		WHERE a.author_id = $1 AND (a.status = 'published' OR v.views IS NOT NULL)
+		ORDER BY 4 DESC, a.published_at DESC NULLS LAST, a.id`
+	rows, err = r.db.QueryContext(ctx, query, authorID, from, to, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article model.ArticleViewStats
+		if err := rows.Scan(&article.ID, &article.Title, &article.Slug, &article.Views, &article.Reads, &article.Reactions); err != nil {
+			return nil, err
+		}
+		stats.Articles = append(stats.Articles, article)
+	}
+	return stats, rows.Err()
+}
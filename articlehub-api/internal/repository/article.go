@@ -0,0 +1,696 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ArticleRepository interface {
+	CreateArticle(ctx context.Context, article *model.Article) error
+	GetArticles(ctx context.Context, filter model.ArticleFilter) ([]model.Article, error)
+	GetArticlesByAuthor(ctx context.Context, authorID string) ([]model.Article, error)
+	GetArticleById(ctx context.Context, id string) (*model.Article, error)
+	GetSitemapPages(ctx context.Context, pageSize int) ([]model.SitemapPage, error)
+	GetRemovedAt(ctx context.Context, filter model.ArticleFilter) (time.Time, error)
+	GetSitemapEntries(ctx context.Context, page, pageSize int) ([]model.SitemapEntry, error)
+	GetVisibleArticle(ctx context.Context, id, viewerID string) (*model.Article, error)
+	ResolveSlug(ctx context.Context, slug string) (string, error)
+	UpdateArticle(ctx context.Context, id string, article *model.Article, editorID string) error
+	RestoreRevision(ctx context.Context, article *model.Article, revision *model.ArticleRevision, editorID string) (*model.ArticleRevision, error)
+	DeleteArticle(ctx context.Context, id string) error
+
+	GetRevisions(ctx context.Context, articleID string) ([]model.ArticleRevision, error)
+	GetRevision(ctx context.Context, articleID string, number int) (*model.ArticleRevision, error)
+	PruneRevisions(ctx context.Context, keep int, olderThan time.Time) (int64, error)
+
+	TransitionArticle(ctx context.Context, article *model.Article, to string, publishAt *time.Time, actorID string) error
+	GetTransitions(ctx context.Context, articleID string) ([]model.ArticleTransition, error)
+	PublishDueArticles(ctx context.Context, now time.Time) (int, error)
+
+	GetArticlesWithoutStats(ctx context.Context, limit int) ([]model.Article, error)
+	UpdateStats(ctx context.Context, article *model.Article) error
+}
+
+type articleRepository struct {
+	db *sql.DB
+}
+
+func NewArticleRepository(db *sql.DB) ArticleRepository {
+	return &articleRepository{db: db}
+}
+
+const articleColumns = `id, author_id, COALESCE((SELECT u.name FROM users u WHERE u.id = articles.author_id), ''),
+	title, slug, body, body_html, status, publish_at, published_at, version, created_at, updated_at,
+	COALESCE(word_count, 0), COALESCE(reading_time, 0), COALESCE(toc, '[]'),
+	COALESCE((SELECT string_agg(t.name, ',' ORDER BY t.name) FROM article_tags at JOIN tags t ON t.id = at.tag_id WHERE at.article_id = articles.id), ''),
+	COALESCE((SELECT string_agg(ac.category_id::text, ',') FROM article_categories ac WHERE ac.article_id = articles.id), ''),
+	COALESCE((SELECT jsonb_object_agg(rc.reaction, rc.count) FROM article_reaction_counts rc WHERE rc.article_id = articles.id AND rc.count > 0), '{}'),
+	COALESCE((SELECT to_jsonb(m) FROM media m WHERE m.id = articles.cover_media_id), 'null')`
+
+// visibleTo restricts articles to the published ones, plus every article of
+// the viewer. Anonymous viewers pass an empty ID.
+const visibleTo = `(status = 'published' OR author_id = NULLIF($%d, '')::uuid)`
+
+func scanArticle(row interface{ Scan(...any) error }) (*model.Article, error) {
+	var (
+		article          model.Article
+		tags, categories string
+		toc, reactions   []byte
+		cover            []byte
+	)
+	err := row.Scan(&article.ID, &article.AuthorID, &article.AuthorName, &article.Title, &article.Slug, &article.Body, &article.BodyHTML,
+		&article.Status, &article.PublishAt, &article.PublishedAt, &article.Version, &article.CreatedAt, &article.UpdatedAt,
+		&article.WordCount, &article.ReadingTime, &toc, &tags, &categories, &reactions, &cover)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(toc, &article.TOC); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(reactions, &article.Reactions); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(cover, &article.Cover); err != nil {
+		return nil, err
+	}
+	article.Tags = splitList(tags)
+	article.CategoryIDs = splitList(categories)
+	return &article, nil
+}
+
+// coverID returns the ID of an article's cover image, nil for none.
+func coverID(article *model.Article) *string {
+	if article.Cover == nil {
+		return nil
+	}
+	return &article.Cover.ID
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
+// CreateArticle inserts the article along with its first revision.
+func (r *articleRepository) CreateArticle(ctx context.Context, article *model.Article) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var slugBase string
+	article.Slug, slugBase, err = uniqueSlug(ctx, tx, articleSlugs, article.ID, article.Title)
+	if err != nil {
+		return fmt.Errorf("failed to create article: %w", err)
+	}
+
+	toc, err := json.Marshal(article.TOC)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO articles (id, author_id, title, slug, slug_base, body, body_html, status, word_count, reading_time, toc, cover_media_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW()) RETURNING version, created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query, article.ID, article.AuthorID, article.Title, article.Slug, slugBase, article.Body, article.BodyHTML, article.Status,
+		article.WordCount, article.ReadingTime, string(toc), coverID(article)).
+		Scan(&article.Version, &article.CreatedAt, &article.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create article: %w", err)
+	}
+
+	if err := syncTaxonomy(ctx, tx, article); err != nil {
+		return err
+	}
+	if _, err := insertRevision(ctx, tx, article, article.AuthorID, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetArticles lists published articles, newest first, along with the
+// viewer's own unpublished ones.
+func (r *articleRepository) GetArticles(ctx context.Context, filter model.ArticleFilter) ([]model.Article, error) {
+	args := []any{filter.ViewerID}
+	conditions := []string{fmt.Sprintf(visibleTo, 1)}
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.AuthorID != "" {
+		where("author_id = $%d", filter.AuthorID)
+	}
+	if len(filter.Tags) > 0 {
+		tagIDs, err := r.resolveTagFilter(ctx, filter.Tags, filter.AnyTag)
+		if err != nil {
+			return nil, err
+		}
+		if len(tagIDs) == 0 {
+			return []model.Article{}, nil
+		}
+		if filter.AnyTag {
+			where("id IN (SELECT article_id FROM article_tags WHERE tag_id = ANY($%d::text[]::uuid[]))", tagIDs)
+		} else {
+			where("id IN (SELECT article_id FROM article_tags WHERE tag_id = ANY($%d::text[]::uuid[])"+
+				fmt.Sprintf(" GROUP BY article_id HAVING COUNT(*) = %d)", len(tagIDs)), tagIDs)
+		}
+	}
+	if filter.CategoryID != "" {
+		where(`id IN (
+			SELECT article_id FROM article_categories WHERE category_id IN (
+				WITH RECURSIVE tree AS (
+					SELECT id FROM categories WHERE id = $%d
+					UNION ALL
+					SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+				)
+				SELECT id FROM tree
+			)
+		)`, filter.CategoryID)
+	}
+
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY COALESCE(published_at, created_at) DESC`
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, filter.Limit)
+	}
+	articles, err := r.queryArticles(ctx, query, args...)
+	if articles == nil && err == nil {
+		articles = []model.Article{}
+	}
+	return articles, err
+}
+
+// resolveTagFilter maps normalised tag names, following aliases, to distinct
+// tag IDs. Unknown tags are skipped, unless all tags are required in which
+// case no ID is returned since nothing can match.
+func (r *articleRepository) resolveTagFilter(ctx context.Context, names []string, anyTag bool) ([]string, error) {
+	query := `SELECT COALESCE(
+			(SELECT tag_id::text FROM tag_aliases WHERE alias = n),
+			(SELECT id::text FROM tags WHERE name = n)
+		) FROM unnest($1::text[]) AS n`
+	rows, err := r.db.QueryContext(ctx, query, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := map[string]bool{}
+	var ids []string
+	for rows.Next() {
+		var id sql.NullString
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		if !id.Valid {
+			if !anyTag {
+				return nil, nil
+			}
+			continue
+		}
+		if !seen[id.String] {
+			seen[id.String] = true
+			ids = append(ids, id.String)
+		}
+	}
+	return ids, rows.Err()
+}
+
+func (r *articleRepository) GetArticlesByAuthor(ctx context.Context, authorID string) ([]model.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE author_id = $1 ORDER BY created_at DESC`
+	return r.queryArticles(ctx, query, authorID)
+}
+
+func (r *articleRepository) queryArticles(ctx context.Context, query string, args ...any) ([]model.Article, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var articles []model.Article
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, *article)
+	}
+	return articles, rows.Err()
+}
+
+// GetRemovedAt returns the last time an article may have stopped matching
+// filter among the published articles, or the zero time if none ever did.
+// Unpublished and deleted articles are tracked per author. Tag and category
+// filters are also left by editing an article, so for those the latest edit
+// of any published article counts too.
+func (r *articleRepository) GetRemovedAt(ctx context.Context, filter model.ArticleFilter) (time.Time, error) {
+	query := `SELECT GREATEST(
+			(SELECT MAX(removed_at) FROM published_removals WHERE $1 = '' OR author_id = NULLIF($1, '')::uuid),
+			(SELECT MAX(updated_at) FROM articles WHERE $2 AND status = 'published'))`
+	scoped := len(filter.Tags) > 0 || filter.CategoryID != ""
+	var removedAt sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, filter.AuthorID, scoped).Scan(&removedAt); err != nil {
+		return time.Time{}, err
+	}
+	return removedAt.Time, nil
+}
+
+// sitemapOrder lists published articles oldest first, so that new articles
+// only ever change the last page of a split sitemap.
+const sitemapOrder = `ORDER BY published_at, id`
+
+// GetSitemapPages splits the published articles into pages of pageSize and
+// returns when each page last changed. There are no pages without published
+// articles. The times only cover edits to the articles still on a page, not
+// articles that left it, so they are hints for crawlers and not used to
+// answer conditional requests.
+func (r *articleRepository) GetSitemapPages(ctx context.Context, pageSize int) ([]model.SitemapPage, error) {
+	query := `SELECT page, MAX(updated_at) FROM (
+			SELECT (ROW_NUMBER() OVER (` + sitemapOrder + `) - 1) / $1 + 1 AS page, updated_at
+			FROM articles WHERE status = 'published'
+		) p
+		GROUP BY page ORDER BY page`
+	rows, err := r.db.QueryContext(ctx, query, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pages []model.SitemapPage
+	for rows.Next() {
+		var page model.SitemapPage
+		if err := rows.Scan(&page.Number, &page.LastModified); err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+	return pages, rows.Err()
+}
+
+// GetSitemapEntries returns page number page, counted from 1, of the
+// published articles.
+func (r *articleRepository) GetSitemapEntries(ctx context.Context, page, pageSize int) ([]model.SitemapEntry, error) {
+	query := `SELECT slug, updated_at FROM articles WHERE status = 'published' ` + sitemapOrder + ` LIMIT $1 OFFSET $2`
+	rows, err := r.db.QueryContext(ctx, query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.SitemapEntry
+	for rows.Next() {
+		var entry model.SitemapEntry
+		if err := rows.Scan(&entry.Slug, &entry.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetArticleById returns the article regardless of its status. Use
+// GetVisibleArticle when serving readers.
+func (r *articleRepository) GetArticleById(ctx context.Context, id string) (*model.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE id = $1`
+	return r.queryArticle(ctx, query, id)
+}
+
+// GetVisibleArticle returns the article if it is published or belongs to the
+// viewer, and reports "article not found" otherwise.
+func (r *articleRepository) GetVisibleArticle(ctx context.Context, id, viewerID string) (*model.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE id = $1 AND ` + fmt.Sprintf(visibleTo, 2)
+	return r.queryArticle(ctx, query, id, viewerID)
+}
+
+// ResolveSlug returns the ID of the article currently or formerly known by
+// slug, regardless of its status.
+func (r *articleRepository) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	id, err := resolveSlug(ctx, r.db, articleSlugs, slug)
+	if err == nil && id == "" {
+		return "", fmt.Errorf("article not found")
+	}
+	return id, err
+}
+
+func (r *articleRepository) queryArticle(ctx context.Context, query string, args ...any) (*model.Article, error) {
+	article, err := scanArticle(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		// 22P02: the ID is not a valid uuid, so no article has it.
+		var pgErr *pgconn.PgError
+		if err == sql.ErrNoRows || errors.As(err, &pgErr) && pgErr.Code == "22P02" {
+			return nil, fmt.Errorf("article not found")
+		}
+		return nil, err
+	}
+	return article, nil
+}
+
+// UpdateArticle saves the article's content and records it as a new
+// revision made by editorID. The update only applies if the article is still
+// at article.Version, otherwise "version mismatch" is reported.
+func (r *articleRepository) UpdateArticle(ctx context.Context, id string, article *model.Article, editorID string) error {
+	_, err := r.saveRevision(ctx, id, article, editorID, nil)
+	return err
+}
+
+// RestoreRevision copies an older revision's content into the article,
+// recording the result as a new revision rather than rewriting history. The
+// caller is expected to have rendered the restored body into article.
+func (r *articleRepository) RestoreRevision(ctx context.Context, article *model.Article, revision *model.ArticleRevision, editorID string) (*model.ArticleRevision, error) {
+	article.Title = revision.Title
+	article.Body = revision.Body
+	return r.saveRevision(ctx, article.ID, article, editorID, &revision.Number)
+}
+
+func (r *articleRepository) saveRevision(ctx context.Context, id string, article *model.Article, editorID string, restoredFrom *int) (*model.ArticleRevision, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Updating the row first also locks it, serialising revision numbers
+	toc, err := json.Marshal(article.TOC)
+	if err != nil {
+		return nil, err
+	}
+	var slug, slugBase string
+	query := `UPDATE articles SET title = $1, body = $2, body_html = $3, word_count = $4, reading_time = $5, toc = $6,
+			cover_media_id = $7, updated_at = NOW(), version = version + 1
+		WHERE id = $8 AND version = $9 RETURNING updated_at, version, slug, COALESCE(slug_base, '')`
+	err = tx.QueryRowContext(ctx, query, article.Title, article.Body, article.BodyHTML, article.WordCount, article.ReadingTime, string(toc),
+		coverID(article), id, article.Version).
+		Scan(&article.UpdatedAt, &article.Version, &slug, &slugBase)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("version mismatch")
+		}
+		return nil, err
+	}
+	// A new title moves the article to a new slug; the old one redirects.
+	if article.Slug, err = renameSlug(ctx, tx, articleSlugs, id, slug, slugBase, article.Title); err != nil {
+		return nil, err
+	}
+
+	if err := syncTaxonomy(ctx, tx, article); err != nil {
+		return nil, err
+	}
+	revision, err := insertRevision(ctx, tx, article, editorID, restoredFrom)
+	if err != nil {
+		return nil, err
+	}
+	return revision, tx.Commit()
+}
+
+// syncTaxonomy replaces the article's tags and categories with article.Tags
+// and article.CategoryIDs. Tags must be normalised already; they are resolved
+// through aliases, created on first use, and article.Tags is updated with
+// their canonical names.
+func syncTaxonomy(ctx context.Context, tx *sql.Tx, article *model.Article) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM article_tags WHERE article_id = $1`, article.ID); err != nil {
+		return err
+	}
+
+	canonical := []string{}
+	seen := map[string]bool{}
+	for _, name := range article.Tags {
+		tagID, tagName, err := resolveTag(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+		if seen[tagID] {
+			continue
+		}
+		seen[tagID] = true
+		canonical = append(canonical, tagName)
+
+		query := `INSERT INTO article_tags (article_id, tag_id) VALUES ($1, $2)`
+		if _, err := tx.ExecContext(ctx, query, article.ID, tagID); err != nil {
+			return fmt.Errorf("failed to tag article: %w", err)
+		}
+	}
+	article.Tags = canonical
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM article_categories WHERE article_id = $1`, article.ID); err != nil {
+		return err
+	}
+	for _, categoryID := range article.CategoryIDs {
+		query := `INSERT INTO article_categories (article_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, article.ID, categoryID); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") {
+				return fmt.Errorf("category not found")
+			}
+			return fmt.Errorf("failed to categorise article: %w", err)
+		}
+	}
+	return nil
+}
+
+func insertRevision(ctx context.Context, tx *sql.Tx, article *model.Article, editorID string, restoredFrom *int) (*model.ArticleRevision, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &model.ArticleRevision{
+		ID:           id.String(),
+		ArticleID:    article.ID,
+		EditorID:     editorID,
+		Title:        article.Title,
+		Body:         article.Body,
+		RestoredFrom: restoredFrom,
+	}
+
+	query := `INSERT INTO article_revisions (id, article_id, number, editor_id, title, body, restored_from, created_at)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(number), 0) + 1 FROM article_revisions WHERE article_id = $2), $3, $4, $5, $6, NOW())
+		RETURNING number, created_at`
+	err = tx.QueryRowContext(ctx, query, revision.ID, revision.ArticleID, revision.EditorID, revision.Title, revision.Body, revision.RestoredFrom).
+		Scan(&revision.Number, &revision.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record article revision: %w", err)
+	}
+	return revision, nil
+}
+
+// GetRevisions lists an article's revisions, newest first, without their
+// bodies.
+func (r *articleRepository) GetRevisions(ctx context.Context, articleID string) ([]model.ArticleRevision, error) {
+	query := `SELECT id, article_id, number, COALESCE(editor_id::text, ''), title, restored_from, created_at
+		FROM article_revisions WHERE article_id = $1 ORDER BY number DESC`
+	rows, err := r.db.QueryContext(ctx, query, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []model.ArticleRevision{}
+	for rows.Next() {
+		var rev model.ArticleRevision
+		if err := rows.Scan(&rev.ID, &rev.ArticleID, &rev.Number, &rev.EditorID, &rev.Title, &rev.RestoredFrom, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *articleRepository) GetRevision(ctx context.Context, articleID string, number int) (*model.ArticleRevision, error) {
+	query := `SELECT id, article_id, number, COALESCE(editor_id::text, ''), title, body, restored_from, created_at
+		FROM article_revisions WHERE article_id = $1 AND number = $2`
+	var rev model.ArticleRevision
+	err := r.db.QueryRowContext(ctx, query, articleID, number).
+		Scan(&rev.ID, &rev.ArticleID, &rev.Number, &rev.EditorID, &rev.Title, &rev.Body, &rev.RestoredFrom, &rev.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("revision not found")
+		}
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// PruneRevisions deletes revisions beyond the newest keep of each article
+// (keep <= 0 means no limit) and revisions created before olderThan (zero
+// means no limit). The latest revision of an article is never deleted.
+func (r *articleRepository) PruneRevisions(ctx context.Context, keep int, olderThan time.Time) (int64, error) {
+	if keep <= 0 && olderThan.IsZero() {
+		return 0, nil
+	}
+
+	query := `DELETE FROM article_revisions WHERE id IN (
+			SELECT id FROM (
+				SELECT id, created_at, ROW_NUMBER() OVER (PARTITION BY article_id ORDER BY number DESC) AS position
+				FROM article_revisions
+			) ranked
+			WHERE position > 1 AND (
+				($1 > 0 AND position > $1) OR
+				($2::timestamptz IS NOT NULL AND created_at < $2)
+			)
+		)`
+	var cutoff *time.Time
+	if !olderThan.IsZero() {
+		cutoff = &olderThan
+	}
+	result, err := r.db.ExecContext(ctx, query, keep, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune article revisions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *articleRepository) DeleteArticle(ctx context.Context, id string) error {
+	query := `DELETE FROM articles WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return fmt.Errorf("article not found")
+	}
+	return nil
+}
+
+// TransitionArticle moves the article to status to and records the change.
+// The update only applies if the article is still in the status it was read
+// with, so concurrent transitions cannot skip the state machine; the loser
+// gets "article status changed". On success article is updated in place.
+func (r *articleRepository) TransitionArticle(ctx context.Context, article *model.Article, to string, publishAt *time.Time, actorID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	from := article.Status
+	query := `UPDATE articles SET
+			status = $1,
+			publish_at = $2,
+			published_at = CASE WHEN $1 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $3 AND status = $4
+		RETURNING status, publish_at, published_at, version, updated_at`
+	err = tx.QueryRowContext(ctx, query, to, publishAt, article.ID, from).
+		Scan(&article.Status, &article.PublishAt, &article.PublishedAt, &article.Version, &article.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("article status changed")
+		}
+		return err
+	}
+
+	if err := insertTransition(ctx, tx, article.ID, from, to, actorID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *articleRepository) GetTransitions(ctx context.Context, articleID string) ([]model.ArticleTransition, error) {
+	query := `SELECT id, article_id, from_status, to_status, COALESCE(actor_id::text, ''), created_at
+		FROM article_transitions WHERE article_id = $1 ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []model.ArticleTransition{}
+	for rows.Next() {
+		var t model.ArticleTransition
+		if err := rows.Scan(&t.ID, &t.ArticleID, &t.FromStatus, &t.ToStatus, &t.ActorID, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
+
+// PublishDueArticles publishes every scheduled article whose publish_at has
+// passed and returns how many were published.
+func (r *articleRepository) PublishDueArticles(ctx context.Context, now time.Time) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE articles SET status = 'published', published_at = publish_at, updated_at = NOW(), version = version + 1
+		WHERE id IN (
+			SELECT id FROM articles WHERE status = 'scheduled' AND publish_at <= $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`
+	rows, err := tx.QueryContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if err := insertTransition(ctx, tx, id, model.ArticleStatusScheduled, model.ArticleStatusPublished, ""); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), tx.Commit()
+}
+
+func insertTransition(ctx context.Context, tx *sql.Tx, articleID, from, to, actorID string) error {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO article_transitions (id, article_id, from_status, to_status, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, NOW())`
+	if _, err := tx.ExecContext(ctx, query, id.String(), articleID, from, to, actorID); err != nil {
+		return fmt.Errorf("failed to record article transition: %w", err)
+	}
+	return nil
+}
+
+// GetArticlesWithoutStats returns up to limit articles saved before their
+// statistics were computed on write.
+func (r *articleRepository) GetArticlesWithoutStats(ctx context.Context, limit int) ([]model.Article, error) {
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE toc IS NULL LIMIT $1`
+	return r.queryArticles(ctx, query, limit)
+}
+
+// UpdateStats stores a fresh rendering of the article body and its
+// statistics, unless the article was edited in the meantime. Since the
+// content itself is unchanged, neither the version nor updated_at move.
+func (r *articleRepository) UpdateStats(ctx context.Context, article *model.Article) error {
+	toc, err := json.Marshal(article.TOC)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE articles SET body_html = $1, word_count = $2, reading_time = $3, toc = $4 WHERE id = $5 AND version = $6`
+	_, err = r.db.ExecContext(ctx, query, article.BodyHTML, article.WordCount, article.ReadingTime, string(toc), article.ID, article.Version)
+	return err
+}
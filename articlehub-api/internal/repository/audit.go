@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/model"
+)
+
+type AuditRepository interface {
+	CreateEntry(ctx context.Context, entry *model.AuditEntry) error
+	ListEntries(ctx context.Context, filter model.AuditFilter) ([]model.AuditEntry, error)
+	DeleteEntriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) CreateEntry(ctx context.Context, entry *model.AuditEntry) error {
+	var changes []byte
+	if len(entry.Changes) > 0 {
+		var err error
+		if changes, err = json.Marshal(entry.Changes); err != nil {
+			return fmt.Errorf("failed to encode audit changes: %w", err)
+		}
+	}
+
+	query := `INSERT INTO audit_logs (id, actor_id, subject_id, action, target_type, target_id, ip, user_agent, changes, created_at)
+		VALUES ($1, NULLIF($2, '')::uuid, NULLIF($3, '')::uuid, $4, $5, $6, $7, $8, $9, NOW()) RETURNING created_at`
+	err := r.db.QueryRowContext(ctx, query, entry.ID, entry.ActorID, entry.SubjectID, entry.Action, entry.TargetType,
+		entry.TargetID, entry.IP, entry.UserAgent, changes).Scan(&entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *auditRepository) ListEntries(ctx context.Context, filter model.AuditFilter) ([]model.AuditEntry, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.ActorID != "" {
+		where("actor_id = $%d", filter.ActorID)
+	}
+	if filter.SubjectID != "" {
+		where("subject_id = $%d", filter.SubjectID)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		where("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		where("target_id = $%d", filter.TargetID)
+	}
+	if filter.IP != "" {
+		where("ip = $%d", filter.IP)
+	}
+	if !filter.From.IsZero() {
+		where("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("created_at < $%d", filter.To)
+	}
+
+	query := `SELECT id, COALESCE(actor_id::text, ''), COALESCE(subject_id::text, ''), action, target_type, target_id, ip, user_agent, changes, created_at FROM audit_logs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	for rows.Next() {
+		var (
+			entry   model.AuditEntry
+			changes []byte
+		)
+		if err := rows.Scan(&entry.ID, &entry.ActorID, &entry.SubjectID, &entry.Action, &entry.TargetType, &entry.TargetID,
+			&entry.IP, &entry.UserAgent, &changes, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+				return nil, fmt.Errorf("failed to decode audit changes: %w", err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *auditRepository) DeleteEntriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM audit_logs WHERE created_at < $1`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete audit entries: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"articlehub-api/internal/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment *model.Comment) error
+	GetComment(ctx context.Context, id string) (*model.Comment, error)
+	ListThreads(ctx context.Context, articleID, cursor string, limit, maxReplies, maxDepth int) ([]model.Comment, string, error)
+	ListReplies(ctx context.Context, parentID, cursor string, limit int) ([]model.Comment, string, error)
+	GetCommentsByAuthor(ctx context.Context, authorID string) ([]model.Comment, error)
+	UpdateComment(ctx context.Context, comment *model.Comment) error
+	DeleteComment(ctx context.Context, id string) error
+}
+
+type commentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+const commentColumns = `id, article_id, COALESCE(author_id::text, ''), parent_id, root_id, depth, body, body_html,
+	edited_at, deleted_at, created_at, updated_at,
+	COALESCE((SELECT jsonb_object_agg(rc.reaction, rc.count) FROM comment_reaction_counts rc WHERE rc.comment_id = comments.id AND rc.count > 0), '{}'),
+	(SELECT COUNT(*) FROM comments r WHERE r.parent_id = comments.id)`
+
+func scanComment(row interface{ Scan(...any) error }) (*model.Comment, error) {
+	var (
+		comment   model.Comment
+		reactions []byte
+	)
+	err := row.Scan(&comment.ID, &comment.ArticleID, &comment.AuthorID, &comment.ParentID, &comment.RootID,
+		&comment.Depth, &comment.Body, &comment.BodyHTML, &comment.EditedAt, &comment.DeletedAt,
+		&comment.CreatedAt, &comment.UpdatedAt, &reactions, &comment.ReplyCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(reactions, &comment.Reactions); err != nil {
+		return nil, err
+	}
+	comment.Edited = comment.EditedAt != nil
+	if comment.DeletedAt != nil {
+		comment.Deleted = true
+		comment.AuthorID = ""
+	}
+	return &comment, nil
+}
+
+// CreateComment stores a new comment. RootID and Depth must already be set
+// from the parent, or to the comment itself and 0 for a top-level comment.
+func (r *commentRepository) CreateComment(ctx context.Context, comment *model.Comment) error {
+	query := `INSERT INTO comments (id, article_id, author_id, parent_id, root_id, depth, body, body_html, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()) RETURNING created_at, updated_at`
+	err := r.db.QueryRowContext(ctx, query, comment.ID, comment.ArticleID, comment.AuthorID, comment.ParentID,
+		comment.RootID, comment.Depth, comment.Body, comment.BodyHTML).Scan(&comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return nil
+}
+
+func (r *commentRepository) GetComment(ctx context.Context, id string) (*model.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE id = $1`
+	comment, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		// 22P02: the ID is not a valid uuid, so no comment has it.
+		var pgErr *pgconn.PgError
+		if err == sql.ErrNoRows || errors.As(err, &pgErr) && pgErr.Code == "22P02" {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+// ListThreads returns up to limit top-level comments of an article, oldest
+// first and starting after the cursor comment, together with the first
+// maxReplies replies of each thread no deeper than maxDepth. Replies are
+// taken oldest first, so the parent of every reply is among them; the
+// others are paged through with ListReplies. The second result is the
+// cursor of the next page, empty on the last one.
+func (r *commentRepository) ListThreads(ctx context.Context, articleID, cursor string, limit, maxReplies, maxDepth int) ([]model.Comment, string, error) {
+	query := `WITH roots AS (
+			SELECT id FROM comments
+			WHERE article_id = $1 AND parent_id IS NULL AND ($2 = '' OR id > NULLIF($2, '')::uuid)
+			ORDER BY id
+			LIMIT $3
+		), replies AS (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY root_id ORDER BY created_at, id) AS position
+			FROM comments
+			WHERE root_id IN (SELECT id FROM roots) AND parent_id IS NOT NULL AND depth <= $5
+		)
+		SELECT ` + commentColumns + ` FROM comments
+		WHERE id IN (SELECT id FROM roots) OR id IN (SELECT id FROM replies WHERE position <= $4)
+		ORDER BY root_id, created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, articleID, cursor, limit+1, maxReplies, maxDepth)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	comments := []model.Comment{}
+	roots := 0
+	next := ""
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		if comment.ParentID == nil {
+			roots++
+		}
+		// One thread more than requested was fetched to learn whether
+		// another page follows.
+		if roots > limit {
+			next = comments[len(comments)-1].RootID
+			break
+		}
+		comments = append(comments, *comment)
+	}
+	return comments, next, rows.Err()
+}
+
+// ListReplies returns up to limit direct replies of a comment, oldest first
+// and starting after the cursor reply. The second result is the cursor of
+// the next page, empty on the last one.
+func (r *commentRepository) ListReplies(ctx context.Context, parentID, cursor string, limit int) ([]model.Comment, string, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments
+		WHERE parent_id = $1 AND ($2 = '' OR (created_at, id) > (SELECT created_at, id FROM comments WHERE id = NULLIF($2, '')::uuid))
+		ORDER BY created_at, id
+		LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, parentID, cursor, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	replies := []model.Comment{}
+	next := ""
+	for rows.Next() {
+		reply, err := scanComment(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(replies) == limit {
+			next = replies[len(replies)-1].ID
+			break
+		}
+		replies = append(replies, *reply)
+	}
+	return replies, next, rows.Err()
+}
+
+// UpdateComment replaces the body of a comment that has not been deleted
+// and marks it as edited.
+// GetCommentsByAuthor returns every comment a user has written, newest
+// first. Deleted comments have no body left and are not included.
+func (r *commentRepository) GetCommentsByAuthor(ctx context.Context, authorID string) ([]model.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE author_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []model.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *comment)
+	}
+	return comments, rows.Err()
+}
+
+func (r *commentRepository) UpdateComment(ctx context.Context, comment *model.Comment) error {
+	query := `UPDATE comments SET body = $1, body_html = $2, edited_at = NOW(), updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL RETURNING edited_at, updated_at`
+	err := r.db.QueryRowContext(ctx, query, comment.Body, comment.BodyHTML, comment.ID).
+		Scan(&comment.EditedAt, &comment.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("comment not found")
+		}
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	comment.Edited = true
+	return nil
+}
+
+// DeleteComment turns a comment into a placeholder: its body is scrubbed but
+// the row stays so that replies keep their place in the thread.
+func (r *commentRepository) DeleteComment(ctx context.Context, id string) error {
+	query := `UPDATE comments SET body = '', body_html = '', deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return fmt.Errorf("comment not found")
+	}
+	return nil
+}
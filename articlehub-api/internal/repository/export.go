@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"articlehub-api/internal/model"
+)
+
+type ExportRepository interface {
+	CreateExport(ctx context.Context, export *model.DataExport) error
+	GetExport(ctx context.Context, id string) (*model.DataExport, error)
+	ClaimPendingExport(ctx context.Context, staleBefore time.Time) (*model.DataExport, error)
+	CompleteExport(ctx context.Context, id, filePath string, expiresAt time.Time) error
+	FailExport(ctx context.Context, id, reason string) error
+	DeleteExpiredExports(ctx context.Context, now time.Time) ([]string, error)
+}
+
+type exportRepository struct {
+	db *sql.DB
+}
+
+func NewExportRepository(db *sql.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+const exportColumns = `id, user_id, status, COALESCE(file_path, ''), COALESCE(error, ''), created_at, completed_at, expires_at`
+
+func scanExport(row interface{ Scan(...any) error }) (*model.DataExport, error) {
+	var export model.DataExport
+	err := row.Scan(&export.ID, &export.UserID, &export.Status, &export.FilePath, &export.Error,
+		&export.CreatedAt, &export.CompletedAt, &export.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *exportRepository) CreateExport(ctx context.Context, export *model.DataExport) error {
+	query := `INSERT INTO data_exports (id, user_id, status, created_at) VALUES ($1, $2, $3, NOW()) RETURNING created_at`
+	err := r.db.QueryRowContext(ctx, query, export.ID, export.UserID, export.Status).Scan(&export.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+	return nil
+}
+
+func (r *exportRepository) GetExport(ctx context.Context, id string) (*model.DataExport, error) {
+	query := `SELECT ` + exportColumns + ` FROM data_exports WHERE id = $1`
+	export, err := scanExport(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("export not found")
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// ClaimPendingExport marks the oldest pending export as processing and
+// returns it, or returns nil when there is nothing to do. Exports claimed
+// before staleBefore and still processing were left behind by a worker that
+// died, and are claimed again. SKIP LOCKED lets several API instances run
+// the export worker concurrently.
+func (r *exportRepository) ClaimPendingExport(ctx context.Context, staleBefore time.Time) (*model.DataExport, error) {
+	query := `UPDATE data_exports SET status = $1, claimed_at = NOW()
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = $2 OR (status = $1 AND (claimed_at IS NULL OR claimed_at < $3))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + exportColumns
+	export, err := scanExport(r.db.QueryRowContext(ctx, query, model.ExportStatusProcessing, model.ExportStatusPending, staleBefore))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *exportRepository) CompleteExport(ctx context.Context, id, filePath string, expiresAt time.Time) error {
+	query := `UPDATE data_exports SET status = $1, file_path = $2, completed_at = NOW(), expires_at = $3 WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, query, model.ExportStatusReady, filePath, expiresAt, id)
+	return err
+}
+
+func (r *exportRepository) FailExport(ctx context.Context, id, reason string) error {
+	query := `UPDATE data_exports SET status = $1, error = $2, completed_at = NOW() WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, model.ExportStatusFailed, reason, id)
+	return err
+}
+
+// DeleteExpiredExports removes exports whose download link has expired and
+// returns the archive paths so the caller can delete the files.
+func (r *exportRepository) DeleteExpiredExports(ctx context.Context, now time.Time) ([]string, error) {
+	query := `DELETE FROM data_exports WHERE expires_at < $1 RETURNING COALESCE(file_path, '')`
+	rows, err := r.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, rows.Err()
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"articlehub-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type FollowRepository interface {
+	FollowUser(ctx context.Context, followerID, followeeID string) error
+	UnfollowUser(ctx context.Context, followerID, followeeID string) error
+	ListFollowers(ctx context.Context, userID string, limit, offset int) ([]model.FollowUser, error)
+	ListFollowing(ctx context.Context, userID string, limit, offset int) ([]model.FollowUser, error)
+	GetFollowCounts(ctx context.Context, userID string) (*model.FollowCounts, error)
+
+	FollowTag(ctx context.Context, userID, tag string) (string, error)
+	UnfollowTag(ctx context.Context, userID, tag string) error
+	ListFollowedTags(ctx context.Context, userID string) ([]string, error)
+
+	GetFeed(ctx context.Context, userID, cursor string, limit int) ([]model.Article, string, error)
+}
+
+type followRepository struct {
+	db *sql.DB
+}
+
+func NewFollowRepository(db *sql.DB) FollowRepository {
+	return &followRepository{db: db}
+}
+
+// FollowUser is idempotent: following someone again changes nothing.
+func (r *followRepository) FollowUser(ctx context.Context, followerID, followeeID string) error {
+	query := `INSERT INTO user_follows (follower_id, followee_id, created_at) VALUES ($1, $2, NOW())
+		ON CONFLICT DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, followerID, followeeID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+	return nil
+}
+
+// UnfollowUser is idempotent: it succeeds when there was no follow.
+func (r *followRepository) UnfollowUser(ctx context.Context, followerID, followeeID string) error {
+	query := `DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+	return nil
+}
+
+// ListFollowers returns the active users following userID, most recent
+// first.
+func (r *followRepository) ListFollowers(ctx context.Context, userID string, limit, offset int) ([]model.FollowUser, error) {
+	query := `SELECT u.id, u.name, u.avatar_url, f.created_at
+		FROM user_follows f JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = $1 AND u.deleted_at IS NULL
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3`
+	return r.queryFollowUsers(ctx, query, userID, limit, offset)
+}
+
+// ListFollowing returns the active users userID follows, most recent first.
+func (r *followRepository) ListFollowing(ctx context.Context, userID string, limit, offset int) ([]model.FollowUser, error) {
+	query := `SELECT u.id, u.name, u.avatar_url, f.created_at
+		FROM user_follows f JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1 AND u.deleted_at IS NULL
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3`
+	return r.queryFollowUsers(ctx, query, userID, limit, offset)
+}
+
+func (r *followRepository) queryFollowUsers(ctx context.Context, query string, args ...any) ([]model.FollowUser, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []model.FollowUser{}
+	for rows.Next() {
+		var user model.FollowUser
+		if err := rows.Scan(&user.ID, &user.Name, &user.AvatarURL, &user.FollowedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *followRepository) GetFollowCounts(ctx context.Context, userID string) (*model.FollowCounts, error) {
+	query := `SELECT
+			(SELECT COUNT(*) FROM user_follows f JOIN users u ON u.id = f.follower_id
+				WHERE f.followee_id = $1 AND u.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM user_follows f JOIN users u ON u.id = f.followee_id
+				WHERE f.follower_id = $1 AND u.deleted_at IS NULL)`
+	var counts model.FollowCounts
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&counts.Followers, &counts.Following); err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// FollowTag follows a normalised tag, resolving aliases, and returns its
+// canonical name.
+func (r *followRepository) FollowTag(ctx context.Context, userID, tag string) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	tagID, canonical, err := resolveTag(ctx, tx, tag)
+	if err != nil {
+		return "", err
+	}
+	query := `INSERT INTO tag_follows (user_id, tag_id, created_at) VALUES ($1, $2, NOW()) ON CONFLICT DO NOTHING`
+	if _, err := tx.ExecContext(ctx, query, userID, tagID); err != nil {
+		return "", fmt.Errorf("failed to follow tag: %w", err)
+	}
+	return canonical, tx.Commit()
+}
+
+// UnfollowTag is idempotent: it succeeds when the tag was not followed.
+func (r *followRepository) UnfollowTag(ctx context.Context, userID, tag string) error {
+	query := `DELETE FROM tag_follows WHERE user_id = $1 AND tag_id IN (
+			SELECT id FROM tags WHERE name = $2
+			UNION
+			SELECT tag_id FROM tag_aliases WHERE alias = $2
+		)`
+	if _, err := r.db.ExecContext(ctx, query, userID, tag); err != nil {
+		return fmt.Errorf("failed to unfollow tag: %w", err)
+	}
+	return nil
+}
+
+func (r *followRepository) ListFollowedTags(ctx context.Context, userID string) ([]string, error) {
+	query := `SELECT t.name FROM tag_follows f JOIN tags t ON t.id = f.tag_id WHERE f.user_id = $1 ORDER BY t.name`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetFeed returns published articles from the authors and tags userID
+// follows, newest first, starting after cursor. The second result is the
+// cursor of the next page, empty on the last one.
+//
+// Every followed author and tag contributes at most limit candidates read
+// from the top of an index, and only those are merged and sorted, so the
+// query stays cheap however many authors are followed.
+func (r *followRepository) GetFeed(ctx context.Context, userID, cursor string, limit int) ([]model.Article, string, error) {
+	before, beforeID := time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), "ffffffff-ffff-ffff-ffff-ffffffffffff"
+	if cursor != "" {
+		var err error
+		if before, beforeID, err = decodeFeedCursor(cursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	query := `WITH candidates AS (
+			SELECT a.id, a.published_at FROM user_follows f
+			CROSS JOIN LATERAL (
+				SELECT id, published_at FROM articles
+				WHERE author_id = f.followee_id AND status = 'published' AND (published_at, id) < ($2, $3::uuid)
+				ORDER BY published_at DESC, id DESC
+				LIMIT $4
+			) a
+			WHERE f.follower_id = $1
+			UNION
+			SELECT a.id, a.published_at FROM tag_follows f
+			CROSS JOIN LATERAL (
+				SELECT ar.id, ar.published_at FROM article_tags at JOIN articles ar ON ar.id = at.article_id
+				WHERE at.tag_id = f.tag_id AND ar.status = 'published' AND (ar.published_at, ar.id) < ($2, $3::uuid)
+				ORDER BY ar.published_at DESC, ar.id DESC
+				LIMIT $4
+			) a
+			WHERE f.user_id = $1
+		), page AS (
+			SELECT id FROM candidates ORDER BY published_at DESC, id DESC LIMIT $4
+		)
+		SELECT ` + articleColumns + ` FROM articles
+		WHERE id IN (SELECT id FROM page)
+		ORDER BY published_at DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID, before, beforeID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	articles := []model.Article{}
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		articles = append(articles, *article)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(articles) > limit {
+		articles = articles[:limit]
+		last := articles[limit-1]
+		next = encodeFeedCursor(*last.PublishedAt, last.ID)
+	}
+	return articles, next, nil
+}
+
+// Feed cursors are the position of the last article of a page, opaque to
+// clients.
+func encodeFeedCursor(publishedAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(publishedAt.UTC().Format(time.RFC3339Nano) + "," + id))
+}
+
+func decodeFeedCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid cursor")
+	}
+	at, id, ok := strings.Cut(string(raw), ",")
+	if !ok || uuid.Validate(id) != nil {
+		return time.Time{}, "", fmt.Errorf("invalid cursor")
+	}
+	publishedAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid cursor")
+	}
+	return publishedAt, id, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"articlehub-api/internal/model"
+)
+
+type MediaRepository interface {
+	CreateMedia(ctx context.Context, media *model.Media) error
+	GetMedia(ctx context.Context, id string) (*model.Media, error)
+	GetMediaByIDs(ctx context.Context, ownerID string, ids []string) (map[string]model.Media, error)
+	ListMedia(ctx context.Context, ownerID string, limit, offset int) ([]model.Media, error)
+	UpdateMediaAlt(ctx context.Context, media *model.Media) error
+	DeleteMedia(ctx context.Context, id string) error
+}
+
+type mediaRepository struct {
+	db *sql.DB
+}
+
+func NewMediaRepository(db *sql.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+const mediaColumns = `id, owner_id, url, content_type, width, height, size, alt, variants, array_to_string(storage_keys, ','), created_at, updated_at`
+
+func scanMedia(row interface{ Scan(...any) error }) (*model.Media, error) {
+	var media model.Media
+	var variants []byte
+	var keys string
+	err := row.Scan(&media.ID, &media.OwnerID, &media.URL, &media.ContentType, &media.Width, &media.Height, &media.Size,
+		&media.Alt, &variants, &keys, &media.CreatedAt, &media.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(variants, &media.Variants); err != nil {
+		return nil, err
+	}
+	media.Keys = splitList(keys)
+	return &media, nil
+}
+
+func (r *mediaRepository) CreateMedia(ctx context.Context, media *model.Media) error {
+	variants, err := json.Marshal(media.Variants)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO media (id, owner_id, url, content_type, width, height, size, alt, variants, storage_keys, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW()) RETURNING created_at, updated_at`
+	err = r.db.QueryRowContext(ctx, query, media.ID, media.OwnerID, media.URL, media.ContentType, media.Width, media.Height,
+		media.Size, media.Alt, string(variants), media.Keys).
+		Scan(&media.CreatedAt, &media.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create media: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepository) GetMedia(ctx context.Context, id string) (*model.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE id = $1`
+	media, err := scanMedia(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("media not found")
+		}
+		return nil, err
+	}
+	return media, nil
+}
+
+// GetMediaByIDs returns the media among ids that belong to ownerID, keyed by
+// ID. IDs of other users' media are left out.
+func (r *mediaRepository) GetMediaByIDs(ctx context.Context, ownerID string, ids []string) (map[string]model.Media, error) {
+	found := map[string]model.Media{}
+	if len(ids) == 0 {
+		return found, nil
+	}
+
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE owner_id = $1 AND id = ANY($2::text[]::uuid[])`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		found[media.ID] = *media
+	}
+	return found, rows.Err()
+}
+
+// ListMedia returns a user's library, newest first.
+func (r *mediaRepository) ListMedia(ctx context.Context, ownerID string, limit, offset int) ([]model.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE owner_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	library := []model.Media{}
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		library = append(library, *media)
+	}
+	return library, rows.Err()
+}
+
+func (r *mediaRepository) UpdateMediaAlt(ctx context.Context, media *model.Media) error {
+	query := `UPDATE media SET alt = $1, updated_at = NOW() WHERE id = $2 RETURNING updated_at`
+	err := r.db.QueryRowContext(ctx, query, media.Alt, media.ID).Scan(&media.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("media not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteMedia removes a media item from its owner's library, unless an
+// article uses it as cover or refers to it from its body.
+func (r *mediaRepository) DeleteMedia(ctx context.Context, id string) error {
+	query := `DELETE FROM media WHERE id = $1 AND NOT EXISTS (
+			SELECT 1 FROM articles WHERE cover_media_id = $1 OR strpos(body, $2) > 0
+		)`
+	result, err := r.db.ExecContext(ctx, query, id, model.MediaReference+id)
+	if err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("media in use")
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"articlehub-api/internal/model"
+)
+
+type ReactionRepository interface {
+	GetReactions(ctx context.Context, target, targetID, userID string) (*model.ReactionSummary, error)
+	SetReaction(ctx context.Context, target, targetID, userID, reaction string) (*model.ReactionSummary, error)
+	RemoveReaction(ctx context.Context, target, targetID, userID string) (*model.ReactionSummary, error)
+	ReconcileCounts(ctx context.Context) (int64, error)
+}
+
+type reactionRepository struct {
+	db *sql.DB
+}
+
+func NewReactionRepository(db *sql.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+// reactionTable names the tables holding the reactions and counters of one
+// kind of target.
+type reactionTable struct {
+	reactions string
+	counts    string
+	column    string
+}
+
+var reactionTables = map[string]reactionTable{
+	model.ReactionTargetArticle: {reactions: "article_reactions", counts: "article_reaction_counts", column: "article_id"},
+	model.ReactionTargetComment: {reactions: "comment_reactions", counts: "comment_reaction_counts", column: "comment_id"},
+}
+
+func tableFor(target string) (reactionTable, error) {
+	table, ok := reactionTables[target]
+	if !ok {
+		return reactionTable{}, fmt.Errorf("unknown reaction target %q", target)
+	}
+	return table, nil
+}
+
+// GetReactions returns the counters of a target along with userID's own
+// reaction. userID may be empty for anonymous readers.
+func (r *reactionRepository) GetReactions(ctx context.Context, target, targetID, userID string) (*model.ReactionSummary, error) {
+	table, err := tableFor(target)
+	if err != nil {
+		return nil, err
+	}
+	return reactionSummary(ctx, r.db, table, targetID, userID)
+}
+
+// SetReaction makes reaction userID's reaction to the target, replacing any
+// other one. Setting the reaction the user already has changes nothing.
+func (r *reactionRepository) SetReaction(ctx context.Context, target, targetID, userID, reaction string) (*model.ReactionSummary, error) {
+	table, err := tableFor(target)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s, user_id, reaction, created_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT DO NOTHING`, table.reactions, table.column)
+	result, err := tx.ExecContext(ctx, query, targetID, userID, reaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reaction: %w", err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if inserted == 0 {
+		// The user already reacted; the row lock serialises concurrent
+		// changes so that counters cannot drift.
+		var previous string
+		query = fmt.Sprintf(`SELECT reaction FROM %s WHERE %s = $1 AND user_id = $2 FOR UPDATE`, table.reactions, table.column)
+		if err := tx.QueryRowContext(ctx, query, targetID, userID).Scan(&previous); err != nil {
+			return nil, err
+		}
+		if previous == reaction {
+			return reactionSummary(ctx, tx, table, targetID, userID)
+		}
+
+		query = fmt.Sprintf(`UPDATE %s SET reaction = $1, created_at = NOW() WHERE %s = $2 AND user_id = $3`, table.reactions, table.column)
+		if _, err := tx.ExecContext(ctx, query, reaction, targetID, userID); err != nil {
+			return nil, fmt.Errorf("failed to save reaction: %w", err)
+		}
+		if err := adjustCount(ctx, tx, table, targetID, previous, -1); err != nil {
+			return nil, err
+		}
+	}
+	if err := adjustCount(ctx, tx, table, targetID, reaction, 1); err != nil {
+		return nil, err
+	}
+
+	summary, err := reactionSummary(ctx, tx, table, targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return summary, tx.Commit()
+}
+
+// RemoveReaction withdraws userID's reaction to the target, if any.
+func (r *reactionRepository) RemoveReaction(ctx context.Context, target, targetID, userID string) (*model.ReactionSummary, error) {
+	table, err := tableFor(target)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var previous string
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND user_id = $2 RETURNING reaction`, table.reactions, table.column)
+	err = tx.QueryRowContext(ctx, query, targetID, userID).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	if previous != "" {
+		if err := adjustCount(ctx, tx, table, targetID, previous, -1); err != nil {
+			return nil, err
+		}
+	}
+
+	summary, err := reactionSummary(ctx, tx, table, targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return summary, tx.Commit()
+}
+
+// ReconcileCounts recomputes every counter from the reactions themselves,
+// repairing drift such as reactions removed by a cascading user delete. It
+// returns the number of counters that were corrected.
+func (r *reactionRepository) ReconcileCounts(ctx context.Context) (int64, error) {
+	var fixed int64
+	for _, table := range reactionTables {
+		n, err := r.reconcile(ctx, table)
+		if err != nil {
+			return fixed, err
+		}
+		fixed += n
+	}
+	return fixed, nil
+}
+
+func (r *reactionRepository) reconcile(ctx context.Context, table reactionTable) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Block reaction changes while counting so they cannot be overwritten
+	// by a stale count.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`LOCK TABLE %s IN SHARE MODE`, table.reactions)); err != nil {
+		return 0, err
+	}
+
+	queries := []string{
+		fmt.Sprintf(`INSERT INTO %[2]s (%[3]s, reaction, count)
+			SELECT %[3]s, reaction, COUNT(*) FROM %[1]s GROUP BY %[3]s, reaction
+			ON CONFLICT (%[3]s, reaction) DO UPDATE SET count = EXCLUDED.count
+			WHERE %[2]s.count <> EXCLUDED.count`, table.reactions, table.counts, table.column),
+		fmt.Sprintf(`UPDATE %[2]s c SET count = 0
+			WHERE c.count <> 0 AND NOT EXISTS (
+				SELECT 1 FROM %[1]s r WHERE r.%[3]s = c.%[3]s AND r.reaction = c.reaction
+			)`, table.reactions, table.counts, table.column),
+	}
+
+	var fixed int64
+	for _, query := range queries {
+		result, err := tx.ExecContext(ctx, query)
+		if err != nil {
+			return 0, fmt.Errorf("failed to reconcile %s: %w", table.counts, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		fixed += n
+	}
+	return fixed, tx.Commit()
+}
+
+func adjustCount(ctx context.Context, tx *sql.Tx, table reactionTable, targetID, reaction string, delta int) error {
+	query := fmt.Sprintf(`INSERT INTO %[1]s (%[2]s, reaction, count) VALUES ($1, $2, GREATEST($3, 0))
+		ON CONFLICT (%[2]s, reaction) DO UPDATE SET count = GREATEST(%[1]s.count + $3, 0)`, table.counts, table.column)
+	if _, err := tx.ExecContext(ctx, query, targetID, reaction, delta); err != nil {
+		return fmt.Errorf("failed to update reaction count: %w", err)
+	}
+	return nil
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func reactionSummary(ctx context.Context, q querier, table reactionTable, targetID, userID string) (*model.ReactionSummary, error) {
+	summary := &model.ReactionSummary{Counts: map[string]int{}}
+
+	query := fmt.Sprintf(`SELECT reaction, count FROM %s WHERE %s = $1 AND count > 0`, table.counts, table.column)
+	rows, err := q.QueryContext(ctx, query, targetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			reaction string
+			count    int
+		)
+		if err := rows.Scan(&reaction, &count); err != nil {
+			return nil, err
+		}
+		summary.Counts[reaction] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if userID == "" {
+		return summary, nil
+	}
+	query = fmt.Sprintf(`SELECT reaction FROM %s WHERE %s = $1 AND user_id = $2`, table.reactions, table.column)
+	err = q.QueryRowContext(ctx, query, targetID, userID).Scan(&summary.Reaction)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return summary, nil
+}
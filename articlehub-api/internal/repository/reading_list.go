@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"articlehub-api/internal/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ReadingListRepository interface {
+	ListBookmarks(ctx context.Context, userID string, limit, offset int) ([]model.Bookmark, error)
+	SaveBookmark(ctx context.Context, userID, articleID, note string) (*model.Bookmark, error)
+	DeleteBookmark(ctx context.Context, userID, articleID string) error
+
+	ListReadingLists(ctx context.Context, ownerID string, publicOnly bool) ([]model.ReadingList, error)
+	GetReadingList(ctx context.Context, id string) (*model.ReadingList, error)
+	GetReadingListByToken(ctx context.Context, token string) (*model.ReadingList, error)
+	CreateReadingList(ctx context.Context, list *model.ReadingList) error
+	UpdateReadingList(ctx context.Context, list *model.ReadingList) error
+	DeleteReadingList(ctx context.Context, id string) error
+
+	ListItems(ctx context.Context, listID, viewerID string) ([]model.ReadingListItem, error)
+	SaveItem(ctx context.Context, listID, articleID, note string) (*model.ReadingListItem, error)
+	RemoveItem(ctx context.Context, listID, articleID string) error
+	ReorderItems(ctx context.Context, listID string, articleIDs []string) error
+}
+
+type readingListRepository struct {
+	db *sql.DB
+}
+
+func NewReadingListRepository(db *sql.DB) ReadingListRepository {
+	return &readingListRepository{db: db}
+}
+
+// prefixScanner scans leading columns into its own destinations and hands the
+// remaining ones to the caller, so that scanArticle can be reused on joins.
+type prefixScanner struct {
+	row  interface{ Scan(...any) error }
+	dest []any
+}
+
+func (s prefixScanner) Scan(dest ...any) error {
+	return s.row.Scan(append(s.dest, dest...)...)
+}
+
+// ListBookmarks returns the user's bookmarks, most recent first. Articles
+// that are no longer visible to the user are left out.
+func (r *readingListRepository) ListBookmarks(ctx context.Context, userID string, limit, offset int) ([]model.Bookmark, error) {
+	query := `SELECT b.article_id, b.note, b.added_at, ` + articleColumns + `
+		FROM bookmarks b JOIN articles ON articles.id = b.article_id
+		WHERE b.user_id = $1 AND ` + fmt.Sprintf(visibleTo, 4) + `
+		ORDER BY b.added_at DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookmarks := []model.Bookmark{}
+	for rows.Next() {
+		var bookmark model.Bookmark
+		article, err := scanArticle(prefixScanner{rows, []any{&bookmark.ArticleID, &bookmark.Note, &bookmark.AddedAt}})
+		if err != nil {
+			return nil, err
+		}
+		bookmark.Article = article
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return bookmarks, rows.Err()
+}
+
+// SaveBookmark bookmarks an article, or updates the note of an existing
+// bookmark.
+func (r *readingListRepository) SaveBookmark(ctx context.Context, userID, articleID, note string) (*model.Bookmark, error) {
+	bookmark := &model.Bookmark{ArticleID: articleID, Note: note}
+	query := `INSERT INTO bookmarks (user_id, article_id, note, added_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, article_id) DO UPDATE SET note = EXCLUDED.note
+		RETURNING added_at`
+	if err := r.db.QueryRowContext(ctx, query, userID, articleID, note).Scan(&bookmark.AddedAt); err != nil {
+		return nil, fmt.Errorf("failed to save bookmark: %w", err)
+	}
+	return bookmark, nil
+}
+
+func (r *readingListRepository) DeleteBookmark(ctx context.Context, userID, articleID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM bookmarks WHERE user_id = $1 AND article_id = $2`, userID, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bookmark: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return fmt.Errorf("bookmark not found")
+	}
+	return nil
+}
+
+const readingListColumns = `l.id, l.owner_id, l.name, l.description, l.visibility, l.share_token,
+	(SELECT COUNT(*) FROM reading_list_items i WHERE i.list_id = l.id), l.created_at, l.updated_at`
+
+func scanReadingList(row interface{ Scan(...any) error }) (*model.ReadingList, error) {
+	var list model.ReadingList
+	err := row.Scan(&list.ID, &list.OwnerID, &list.Name, &list.Description, &list.Visibility, &list.ShareToken,
+		&list.ItemCount, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		// 22P02: the ID is not a valid uuid, so no list has it.
+		var pgErr *pgconn.PgError
+		if err == sql.ErrNoRows || errors.As(err, &pgErr) && pgErr.Code == "22P02" {
+			return nil, fmt.Errorf("reading list not found")
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ListReadingLists returns the lists of ownerID in creation order, only the
+// public ones when publicOnly is set.
+func (r *readingListRepository) ListReadingLists(ctx context.Context, ownerID string, publicOnly bool) ([]model.ReadingList, error) {
+	query := `SELECT ` + readingListColumns + ` FROM reading_lists l
+		WHERE l.owner_id = $1 AND (NOT $2 OR l.visibility = 'public')
+		ORDER BY l.created_at`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, publicOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := []model.ReadingList{}
+	for rows.Next() {
+		list, err := scanReadingList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, *list)
+	}
+	return lists, rows.Err()
+}
+
+func (r *readingListRepository) GetReadingList(ctx context.Context, id string) (*model.ReadingList, error) {
+	query := `SELECT ` + readingListColumns + ` FROM reading_lists l WHERE l.id = $1`
+	return scanReadingList(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetReadingListByToken returns the list shared under token, as long as it
+// is not private.
+func (r *readingListRepository) GetReadingListByToken(ctx context.Context, token string) (*model.ReadingList, error) {
+	query := `SELECT ` + readingListColumns + ` FROM reading_lists l WHERE l.share_token = $1 AND l.visibility <> 'private'`
+	return scanReadingList(r.db.QueryRowContext(ctx, query, token))
+}
+
+func (r *readingListRepository) CreateReadingList(ctx context.Context, list *model.ReadingList) error {
+	query := `INSERT INTO reading_lists (id, owner_id, name, description, visibility, share_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW()) RETURNING created_at, updated_at`
+	err := r.db.QueryRowContext(ctx, query, list.ID, list.OwnerID, list.Name, list.Description, list.Visibility, list.ShareToken).
+		Scan(&list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create reading list: %w", err)
+	}
+	return nil
+}
+
+func (r *readingListRepository) UpdateReadingList(ctx context.Context, list *model.ReadingList) error {
+	query := `UPDATE reading_lists SET name = $1, description = $2, visibility = $3, updated_at = NOW()
+		WHERE id = $4 RETURNING updated_at`
+	err := r.db.QueryRowContext(ctx, query, list.Name, list.Description, list.Visibility, list.ID).Scan(&list.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("reading list not found")
+		}
+		return fmt.Errorf("failed to update reading list: %w", err)
+	}
+	return nil
+}
+
+func (r *readingListRepository) DeleteReadingList(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM reading_lists WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reading list: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return fmt.Errorf("reading list not found")
+	}
+	return nil
+}
+
+// ListItems returns the items of a list in order, leaving out articles that
+// viewerID cannot see.
+func (r *readingListRepository) ListItems(ctx context.Context, listID, viewerID string) ([]model.ReadingListItem, error) {
+	query := `SELECT i.article_id, i.position, i.note, i.added_at, ` + articleColumns + `
+		FROM reading_list_items i JOIN articles ON articles.id = i.article_id
+		WHERE i.list_id = $1 AND ` + fmt.Sprintf(visibleTo, 2) + `
+		ORDER BY i.position, i.added_at`
+	rows, err := r.db.QueryContext(ctx, query, listID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.ReadingListItem{}
+	for rows.Next() {
+		var item model.ReadingListItem
+		article, err := scanArticle(prefixScanner{rows, []any{&item.ArticleID, &item.Position, &item.Note, &item.AddedAt}})
+		if err != nil {
+			return nil, err
+		}
+		item.Article = article
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// SaveItem appends an article to the end of a list, or updates its note when
+// it is already there.
+func (r *readingListRepository) SaveItem(ctx context.Context, listID, articleID, note string) (*model.ReadingListItem, error) {
+	item := &model.ReadingListItem{ArticleID: articleID, Note: note}
+	query := `INSERT INTO reading_list_items (list_id, article_id, position, note, added_at)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM reading_list_items WHERE list_id = $1), $3, NOW())
+		ON CONFLICT (list_id, article_id) DO UPDATE SET note = EXCLUDED.note
+		RETURNING position, added_at`
+	err := r.db.QueryRowContext(ctx, query, listID, articleID, note).Scan(&item.Position, &item.AddedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, fmt.Errorf("reading list not found")
+		}
+		return nil, fmt.Errorf("failed to save reading list item: %w", err)
+	}
+	return item, nil
+}
+
+func (r *readingListRepository) RemoveItem(ctx context.Context, listID, articleID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM reading_list_items WHERE list_id = $1 AND article_id = $2`, listID, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to remove reading list item: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return fmt.Errorf("item not found")
+	}
+	return nil
+}
+
+// ReorderItems gives the list's items the order of articleIDs, which must
+// name every item of the list exactly once.
+func (r *readingListRepository) ReorderItems(ctx context.Context, listID string, articleIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current int
+	query := `SELECT COUNT(*) FROM (SELECT 1 FROM reading_list_items WHERE list_id = $1 FOR UPDATE) i`
+	if err := tx.QueryRowContext(ctx, query, listID).Scan(&current); err != nil {
+		return err
+	}
+	if current != len(articleIDs) {
+		return fmt.Errorf("items mismatch")
+	}
+
+	query = `UPDATE reading_list_items i SET position = o.position
+		FROM unnest($2::text[]) WITH ORDINALITY AS o(article_id, position)
+		WHERE i.list_id = $1 AND i.article_id::text = o.article_id`
+	result, err := tx.ExecContext(ctx, query, listID, articleIDs)
+	if err != nil {
+		return fmt.Errorf("failed to reorder reading list: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated != int64(current) {
+		return fmt.Errorf("items mismatch")
+	}
+	return tx.Commit()
+}
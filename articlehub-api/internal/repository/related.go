@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"articlehub-api/internal/model"
+)
+
+type RelatedRepository interface {
+	GetDocuments(ctx context.Context) ([]model.ArticleDocument, error)
+	ReplaceRecommendations(ctx context.Context, recommendations []model.Recommendation) error
+	GetRelated(ctx context.Context, articleID string, limit int) ([]model.RelatedArticle, error)
+}
+
+type relatedRepository struct {
+	db *sql.DB
+}
+
+func NewRelatedRepository(db *sql.DB) RelatedRepository {
+	return &relatedRepository{db: db}
+}
+
+// GetDocuments returns every published article with its tags and the users
+// who engaged with it through bookmarks, reading lists, reactions or
+// comments. Authors engaging with their own articles are left out.
+func (r *relatedRepository) GetDocuments(ctx context.Context) ([]model.ArticleDocument, error) {
+	query := `SELECT a.id, a.title, a.body,
+			COALESCE((SELECT string_agg(t.name, ',') FROM article_tags at JOIN tags t ON t.id = at.tag_id WHERE at.article_id = a.id), ''),
+			COALESCE((
+				SELECT string_agg(DISTINCT e.user_id::text, ',') FROM (
+					SELECT user_id FROM bookmarks WHERE article_id = a.id
+					UNION ALL
+					SELECT l.owner_id FROM reading_list_items i JOIN reading_lists l ON l.id = i.list_id WHERE i.article_id = a.id
+					UNION ALL
+					SELECT user_id FROM article_reactions WHERE article_id = a.id
+					UNION ALL
+					SELECT author_id FROM comments WHERE article_id = a.id AND author_id IS NOT NULL AND deleted_at IS NULL
+				) e WHERE e.user_id <> a.author_id
+			), '')
+		FROM articles a WHERE a.status = 'published'`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []model.ArticleDocument
+	for rows.Next() {
+		var doc model.ArticleDocument
+		var tags, readers string
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.Body, &tags, &readers); err != nil {
+			return nil, err
+		}
+		doc.Tags = splitList(tags)
+		doc.Readers = splitList(readers)
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// ReplaceRecommendations swaps every stored recommendation for the given
+// ones at once, so readers never see a half refreshed set.
+func (r *relatedRepository) ReplaceRecommendations(ctx context.Context, recommendations []model.Recommendation) error {
+	articleIDs := make([]string, len(recommendations))
+	relatedIDs := make([]string, len(recommendations))
+	scores := make([]float64, len(recommendations))
+	for i, rec := range recommendations {
+		articleIDs[i], relatedIDs[i], scores[i] = rec.ArticleID, rec.RelatedID, rec.Score
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM related_articles`); err != nil {
+		return fmt.Errorf("failed to clear recommendations: %w", err)
+	}
+	// Articles deleted since the documents were read are skipped.
+	query := `INSERT INTO related_articles (article_id, related_id, score)
+		SELECT r.article_id, r.related_id, r.score
+		FROM unnest($1::text[]::uuid[], $2::text[]::uuid[], $3::float8[]) AS r (article_id, related_id, score)
+		WHERE EXISTS (SELECT 1 FROM articles WHERE id = r.article_id)
+			AND EXISTS (SELECT 1 FROM articles WHERE id = r.related_id)`
+	if _, err := tx.ExecContext(ctx, query, articleIDs, relatedIDs, scores); err != nil {
+		return fmt.Errorf("failed to save recommendations: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetRelated returns up to limit published articles recommended for an
+// article, best first. Articles published since the last refresh have no
+// recommendations yet and get the articles sharing most of their tags
+// instead.
+func (r *relatedRepository) GetRelated(ctx context.Context, articleID string, limit int) ([]model.RelatedArticle, error) {
+	query := `SELECT ` + articleColumns + `, ra.score
+		FROM related_articles ra JOIN articles ON articles.id = ra.related_id
+		WHERE ra.article_id = $1 AND articles.status = 'published'
+		ORDER BY ra.score DESC, articles.id
+		LIMIT $2`
+	related, err := r.queryRelated(ctx, query, articleID, limit)
+	if err != nil || len(related) > 0 {
+		return related, err
+	}
+
+	// Shared tags scored like the recommender does, as the cosine
+	// similarity of the two tag sets.
+	query = `WITH own AS (SELECT tag_id FROM article_tags WHERE article_id = $1),
+		shared AS (
+			SELECT at.article_id, COUNT(*)::float8 / sqrt(
+				(SELECT COUNT(*) FROM own) * (SELECT COUNT(*) FROM article_tags WHERE article_id = at.article_id)
+			) AS score
+			FROM article_tags at WHERE at.tag_id IN (SELECT tag_id FROM own) AND at.article_id <> $1
+			GROUP BY at.article_id
+		)
+		SELECT ` + articleColumns + `, shared.score
+		FROM shared JOIN articles ON articles.id = shared.article_id
+		WHERE articles.status = 'published'
+		ORDER BY shared.score DESC, articles.published_at DESC, articles.id
+		LIMIT $2`
+	return r.queryRelated(ctx, query, articleID, limit)
+}
+
+func (r *relatedRepository) queryRelated(ctx context.Context, query string, args ...any) ([]model.RelatedArticle, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := []model.RelatedArticle{}
+	for rows.Next() {
+		var score float64
+		article, err := scanArticle(scoredRow{rows, &score})
+		if err != nil {
+			return nil, err
+		}
+		related = append(related, model.RelatedArticle{Article: *article, Score: score})
+	}
+	return related, rows.Err()
+}
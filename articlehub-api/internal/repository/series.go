@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"articlehub-api/internal/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type SeriesRepository interface {
+	ListSeries(ctx context.Context, authorID, viewerID string) ([]model.Series, error)
+	GetSeriesByAuthor(ctx context.Context, authorID string) ([]model.Series, error)
+	GetSeries(ctx context.Context, id string) (*model.Series, error)
+	CreateSeries(ctx context.Context, series *model.Series) error
+	UpdateSeries(ctx context.Context, series *model.Series) error
+	DeleteSeries(ctx context.Context, id string) error
+
+	ListParts(ctx context.Context, seriesID, viewerID string) ([]model.SeriesPart, error)
+	AddArticle(ctx context.Context, series *model.Series, articleID string) error
+	RemoveArticle(ctx context.Context, series *model.Series, articleID string) error
+	ReorderArticles(ctx context.Context, series *model.Series, articleIDs []string) error
+	GetNavigation(ctx context.Context, articleID, viewerID string) (*model.SeriesNavigation, error)
+}
+
+type seriesRepository struct {
+	db *sql.DB
+}
+
+func NewSeriesRepository(db *sql.DB) SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+// seriesColumns counts only the parts visible to the viewer passed as $1.
+const seriesColumns = `s.id, s.author_id, s.title, s.description,
+	(SELECT COUNT(*) FROM series_articles sa JOIN articles ON articles.id = sa.article_id
+		WHERE sa.series_id = s.id AND (articles.status = 'published' OR articles.author_id = NULLIF($1, '')::uuid)),
+	s.version, s.created_at, s.updated_at`
+
+func scanSeries(row interface{ Scan(...any) error }) (*model.Series, error) {
+	var series model.Series
+	err := row.Scan(&series.ID, &series.AuthorID, &series.Title, &series.Description, &series.PartCount,
+		&series.Version, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		// 22P02: the ID is not a valid uuid, so no series has it.
+		var pgErr *pgconn.PgError
+		if err == sql.ErrNoRows || errors.As(err, &pgErr) && pgErr.Code == "22P02" {
+			return nil, fmt.Errorf("series not found")
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// ListSeries returns series newest first, only those of authorID unless it
+// is empty. Part counts only include the parts viewerID can see.
+func (r *seriesRepository) ListSeries(ctx context.Context, authorID, viewerID string) ([]model.Series, error) {
+	query := `SELECT ` + seriesColumns + ` FROM series s
+		WHERE ($2 = '' OR s.author_id = NULLIF($2, '')::uuid)
+		ORDER BY s.created_at DESC
+		LIMIT 100`
+	return r.querySeries(ctx, query, viewerID, authorID)
+}
+
+// GetSeriesByAuthor returns every series of a user, newest first, with part
+// counts that include their drafts.
+func (r *seriesRepository) GetSeriesByAuthor(ctx context.Context, authorID string) ([]model.Series, error) {
+	query := `SELECT ` + seriesColumns + ` FROM series s WHERE s.author_id = NULLIF($1, '')::uuid ORDER BY s.created_at DESC`
+	return r.querySeries(ctx, query, authorID)
+}
+
+func (r *seriesRepository) querySeries(ctx context.Context, query string, args ...any) ([]model.Series, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.Series{}
+	for rows.Next() {
+		series, err := scanSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *series)
+	}
+	return list, rows.Err()
+}
+
+// GetSeries returns a series with the number of all of its parts.
+func (r *seriesRepository) GetSeries(ctx context.Context, id string) (*model.Series, error) {
+	query := `SELECT s.id, s.author_id, s.title, s.description,
+			(SELECT COUNT(*) FROM series_articles sa WHERE sa.series_id = s.id),
+			s.version, s.created_at, s.updated_at
+		FROM series s WHERE s.id = $1`
+	return scanSeries(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *seriesRepository) CreateSeries(ctx context.Context, series *model.Series) error {
+	query := `INSERT INTO series (id, author_id, title, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING version, created_at, updated_at`
+	err := r.db.QueryRowContext(ctx, query, series.ID, series.AuthorID, series.Title, series.Description).
+		Scan(&series.Version, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create series: %w", err)
+	}
+	return nil
+}
+
+// UpdateSeries saves the title and description if the series is still at
+// series.Version, otherwise "version mismatch" is reported.
+func (r *seriesRepository) UpdateSeries(ctx context.Context, series *model.Series) error {
+	query := `UPDATE series SET title = $1, description = $2, version = version + 1, updated_at = NOW()
+		WHERE id = $3 AND version = $4 RETURNING version, updated_at`
+	err := r.db.QueryRowContext(ctx, query, series.Title, series.Description, series.ID, series.Version).
+		Scan(&series.Version, &series.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("version mismatch")
+		}
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+	return nil
+}
+
+// DeleteSeries removes a series; its articles are kept.
+func (r *seriesRepository) DeleteSeries(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM series WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete series: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return fmt.Errorf("series not found")
+	}
+	return nil
+}
+
+// ListParts returns the parts of a series visible to viewerID, in order.
+// Positions are renumbered over the visible parts only.
+func (r *seriesRepository) ListParts(ctx context.Context, seriesID, viewerID string) ([]model.SeriesPart, error) {
+	query := `SELECT sa.article_id, articles.title, articles.status, articles.published_at,
+			ROW_NUMBER() OVER (ORDER BY sa.position)
+		FROM series_articles sa JOIN articles ON articles.id = sa.article_id
+		WHERE sa.series_id = $1 AND ` + fmt.Sprintf(visibleTo, 2) + `
+		ORDER BY sa.position`
+	rows, err := r.db.QueryContext(ctx, query, seriesID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parts := []model.SeriesPart{}
+	for rows.Next() {
+		var part model.SeriesPart
+		if err := rows.Scan(&part.ArticleID, &part.Title, &part.Status, &part.PublishedAt, &part.Position); err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, rows.Err()
+}
+
+// AddArticle appends an article to the end of the series.
+func (r *seriesRepository) AddArticle(ctx context.Context, series *model.Series, articleID string) error {
+	return r.changeParts(ctx, series, func(tx *sql.Tx) error {
+		query := `INSERT INTO series_articles (series_id, article_id, position)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM series_articles WHERE series_id = $1))`
+		if _, err := tx.ExecContext(ctx, query, series.ID, articleID); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return fmt.Errorf("article already in a series")
+			}
+			return fmt.Errorf("failed to add article to series: %w", err)
+		}
+		return nil
+	})
+}
+
+// RemoveArticle takes an article out of the series and closes the gap it
+// leaves.
+func (r *seriesRepository) RemoveArticle(ctx context.Context, series *model.Series, articleID string) error {
+	return r.changeParts(ctx, series, func(tx *sql.Tx) error {
+		query := `DELETE FROM series_articles WHERE series_id = $1 AND article_id = $2`
+		result, err := tx.ExecContext(ctx, query, series.ID, articleID)
+		if err != nil {
+			return fmt.Errorf("failed to remove article from series: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil || rowsAffected == 0 {
+			return fmt.Errorf("article not in series")
+		}
+
+		query = `UPDATE series_articles sa SET position = o.position
+			FROM (
+				SELECT article_id, ROW_NUMBER() OVER (ORDER BY position) AS position
+				FROM series_articles WHERE series_id = $1
+			) o
+			WHERE sa.series_id = $1 AND sa.article_id = o.article_id AND sa.position <> o.position`
+		if _, err := tx.ExecContext(ctx, query, series.ID); err != nil {
+			return fmt.Errorf("failed to renumber series: %w", err)
+		}
+		return nil
+	})
+}
+
+// ReorderArticles gives the parts the order of articleIDs, which must name
+// every article of the series exactly once.
+func (r *seriesRepository) ReorderArticles(ctx context.Context, series *model.Series, articleIDs []string) error {
+	return r.changeParts(ctx, series, func(tx *sql.Tx) error {
+		query := `UPDATE series_articles sa SET position = o.position
+			FROM unnest($2::text[]) WITH ORDINALITY AS o(article_id, position)
+			WHERE sa.series_id = $1 AND sa.article_id::text = o.article_id`
+		result, err := tx.ExecContext(ctx, query, series.ID, articleIDs)
+		if err != nil {
+			return fmt.Errorf("failed to reorder series: %w", err)
+		}
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		var total int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM series_articles WHERE series_id = $1`, series.ID).Scan(&total); err != nil {
+			return err
+		}
+		if int(updated) != total || len(articleIDs) != total {
+			return fmt.Errorf("parts mismatch")
+		}
+		return nil
+	})
+}
+
+// changeParts runs change while holding the series row lock, so that
+// concurrent edits of the same series apply one after the other, then bumps
+// the series version. Unless series.Version is 0, the change is rejected with
+// "version mismatch" when the series is no longer at that version.
+func (r *seriesRepository) changeParts(ctx context.Context, series *model.Series, change func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var version int
+	err = tx.QueryRowContext(ctx, `SELECT version FROM series WHERE id = $1 FOR UPDATE`, series.ID).Scan(&version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("series not found")
+		}
+		return err
+	}
+	if series.Version != 0 && version != series.Version {
+		return fmt.Errorf("version mismatch")
+	}
+
+	if err := change(tx); err != nil {
+		return err
+	}
+
+	query := `UPDATE series SET version = version + 1, updated_at = NOW() WHERE id = $1 RETURNING version, updated_at`
+	if err := tx.QueryRowContext(ctx, query, series.ID).Scan(&series.Version, &series.UpdatedAt); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("parts mismatch")
+		}
+		return err
+	}
+	return nil
+}
+
+// GetNavigation returns where an article stands in its series, as seen by
+// viewerID, or nil when the article is not part of a series.
+func (r *seriesRepository) GetNavigation(ctx context.Context, articleID, viewerID string) (*model.SeriesNavigation, error) {
+	nav := &model.SeriesNavigation{}
+	query := `SELECT s.id, s.title FROM series_articles sa JOIN series s ON s.id = sa.series_id WHERE sa.article_id = $1`
+	if err := r.db.QueryRowContext(ctx, query, articleID).Scan(&nav.ID, &nav.Title); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	parts, err := r.ListParts(ctx, nav.ID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	nav.Total = len(parts)
+	for i := range parts {
+		if parts[i].ArticleID != articleID {
+			continue
+		}
+		nav.Position = parts[i].Position
+		if i > 0 {
+			nav.Previous = &parts[i-1]
+		}
+		if i+1 < len(parts) {
+			nav.Next = &parts[i+1]
+		}
+	}
+	return nav, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"articlehub-api/internal/content"
+)
+
+// slugTable describes where the current and the retired slugs of an entity
+// are stored.
+type slugTable struct {
+	table    string
+	history  string
+	idColumn string
+	// fallback is used when the source text yields no slug at all.
+	fallback string
+}
+
+var (
+	articleSlugs = slugTable{table: "articles", history: "article_slug_history", idColumn: "article_id", fallback: "article"}
+	userSlugs    = slugTable{table: "users", history: "user_slug_history", idColumn: "user_id", fallback: "user"}
+)
+
+// slugBase returns the plain slug for text, before any collision suffix.
+func slugBase(t slugTable, text string) string {
+	if base := content.Slugify(text); base != "" {
+		return base
+	}
+	return t.fallback
+}
+
+// uniqueSlug returns a slug for text that no other entity uses or used, by
+// appending -2, -3, ... to the plain slug when needed, along with the plain
+// slug, which is stored as the slug_base of the entity. The entity's own
+// current and retired slugs may be reused. It serialises concurrent callers
+// competing for the same slug until tx ends.
+func uniqueSlug(ctx context.Context, tx *sql.Tx, t slugTable, id, text string) (slug, base string, err error) {
+	base = slugBase(t, text)
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, t.table+":"+base); err != nil {
+		return "", "", err
+	}
+
+	query := fmt.Sprintf(`SELECT slug FROM %[1]s WHERE (slug = $1 OR slug LIKE $2) AND id <> $3
+		UNION SELECT slug FROM %[2]s WHERE (slug = $1 OR slug LIKE $2) AND %[3]s <> $3`, t.table, t.history, t.idColumn)
+	// Slugs only contain letters, digits and hyphens, none of which LIKE
+	// treats specially.
+	rows, err := tx.QueryContext(ctx, query, base, base+"-%", id)
+	if err != nil {
+		return "", "", err
+	}
+	defer rows.Close()
+
+	taken := map[string]bool{}
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return "", "", err
+		}
+		taken[slug] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", "", err
+	}
+
+	slug = base
+	for n := 2; taken[slug]; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	return slug, base, nil
+}
+
+// renameSlug moves the entity to a slug derived from text unless its current
+// slug was made from the same plain slug, currentBase, keeping the old slug
+// in the history so that it still resolves. It returns the slug in use
+// afterwards.
+func renameSlug(ctx context.Context, tx *sql.Tx, t slugTable, id, current, currentBase, text string) (string, error) {
+	if slugBase(t, text) == currentBase {
+		return current, nil
+	}
+
+	slug, base, err := uniqueSlug(ctx, tx, t, id, text)
+	if err != nil {
+		return "", err
+	}
+	if slug != current {
+		query := fmt.Sprintf(`INSERT INTO %s (slug, %s, retired_at) VALUES ($1, $2, NOW())
+			ON CONFLICT (slug) DO UPDATE SET retired_at = NOW()`, t.history, t.idColumn)
+		if _, err := tx.ExecContext(ctx, query, current, id); err != nil {
+			return "", err
+		}
+		// Reclaiming one of the entity's own old slugs takes it out of the history.
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE slug = $1`, t.history), slug); err != nil {
+			return "", err
+		}
+	}
+	query := fmt.Sprintf(`UPDATE %s SET slug = $1, slug_base = $2 WHERE id = $3`, t.table)
+	if _, err := tx.ExecContext(ctx, query, slug, base, id); err != nil {
+		return "", err
+	}
+	return slug, nil
+}
+
+// resolveSlug returns the ID of the entity whose current or retired slug is
+// slug, or "" when there is none.
+func resolveSlug(ctx context.Context, q querier, t slugTable, slug string) (string, error) {
+	query := fmt.Sprintf(`SELECT id::text FROM %[1]s WHERE slug = $1
+		UNION ALL SELECT %[3]s::text FROM %[2]s WHERE slug = $1
+		LIMIT 1`, t.table, t.history, t.idColumn)
+	var id string
+	if err := q.QueryRowContext(ctx, query, slug).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"articlehub-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type TagRepository interface {
+	ListTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error)
+	ListAliases(ctx context.Context) ([]model.TagAlias, error)
+	CreateAlias(ctx context.Context, alias, tag string) (*model.TagAlias, error)
+	DeleteAlias(ctx context.Context, alias string) error
+
+	ListCategories(ctx context.Context) ([]model.Category, error)
+	GetCategory(ctx context.Context, id string) (*model.Category, error)
+	CreateCategory(ctx context.Context, category *model.Category) error
+	UpdateCategory(ctx context.Context, category *model.Category) error
+	DeleteCategory(ctx context.Context, id string) error
+	IsDescendant(ctx context.Context, id, rootID string) (bool, error)
+}
+
+type tagRepository struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// ListTags returns tags ordered by the number of published articles using
+// them, optionally restricted to names starting with prefix.
+func (r *tagRepository) ListTags(ctx context.Context, prefix string, limit int) ([]model.Tag, error) {
+	query := `SELECT t.id, t.name, COUNT(a.id)
+		FROM tags t
+		LEFT JOIN article_tags at ON at.tag_id = t.id
+		LEFT JOIN articles a ON a.id = at.article_id AND a.status = 'published'
+		WHERE t.name LIKE $1 || '%'
+		GROUP BY t.id, t.name
+		ORDER BY COUNT(a.id) DESC, t.name
+		LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.ArticleCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func (r *tagRepository) ListAliases(ctx context.Context) ([]model.TagAlias, error) {
+	query := `SELECT a.alias, t.name, a.created_at FROM tag_aliases a JOIN tags t ON t.id = a.tag_id ORDER BY a.alias`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []model.TagAlias{}
+	for rows.Next() {
+		var alias model.TagAlias
+		if err := rows.Scan(&alias.Alias, &alias.Tag, &alias.CreatedAt); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, rows.Err()
+}
+
+// CreateAlias makes alias resolve to tag from now on. Both must already be
+// normalised. If alias was itself a tag, its articles and followers move over
+// to tag and it is removed, so existing content follows the new spelling.
+func (r *tagRepository) CreateAlias(ctx context.Context, alias, tag string) (*model.TagAlias, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tagID, canonical, err := resolveTag(ctx, tx, tag)
+	if err != nil {
+		return nil, err
+	}
+	if canonical == alias {
+		return nil, fmt.Errorf("alias would point to itself")
+	}
+
+	var oldID string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM tags WHERE name = $1`, alias).Scan(&oldID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if oldID != "" {
+		queries := []string{
+			`INSERT INTO article_tags (article_id, tag_id) SELECT article_id, $1 FROM article_tags WHERE tag_id = $2 ON CONFLICT DO NOTHING`,
+			`INSERT INTO tag_follows (user_id, tag_id, created_at) SELECT user_id, $1, created_at FROM tag_follows WHERE tag_id = $2 ON CONFLICT DO NOTHING`,
+			`UPDATE tag_aliases SET tag_id = $1 WHERE tag_id = $2`,
+			`DELETE FROM tags WHERE id = $2`,
+		}
+		for _, query := range queries {
+			if _, err := tx.ExecContext(ctx, query, tagID, oldID); err != nil {
+				return nil, fmt.Errorf("failed to merge tag %q: %w", alias, err)
+			}
+		}
+	}
+
+	created := &model.TagAlias{Alias: alias, Tag: canonical}
+	query := `INSERT INTO tag_aliases (alias, tag_id, created_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (alias) DO UPDATE SET tag_id = EXCLUDED.tag_id
+		RETURNING created_at`
+	if err := tx.QueryRowContext(ctx, query, alias, tagID).Scan(&created.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create tag alias: %w", err)
+	}
+	return created, tx.Commit()
+}
+
+func (r *tagRepository) DeleteAlias(ctx context.Context, alias string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tag_aliases WHERE alias = $1`, alias)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return fmt.Errorf("alias not found")
+	}
+	return nil
+}
+
+// resolveTag returns the ID and canonical name of a normalised tag, following
+// aliases and creating the tag on first use.
+func resolveTag(ctx context.Context, tx *sql.Tx, name string) (string, string, error) {
+	var id, canonical string
+	query := `SELECT t.id, t.name FROM tag_aliases a JOIN tags t ON t.id = a.tag_id WHERE a.alias = $1`
+	err := tx.QueryRowContext(ctx, query, name).Scan(&id, &canonical)
+	if err == nil {
+		return id, canonical, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", "", err
+	}
+
+	newID, err := uuid.NewV7()
+	if err != nil {
+		return "", "", err
+	}
+	query = `INSERT INTO tags (id, name, created_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id, name`
+	if err := tx.QueryRowContext(ctx, query, newID.String(), name).Scan(&id, &canonical); err != nil {
+		return "", "", fmt.Errorf("failed to create tag %q: %w", name, err)
+	}
+	return id, canonical, nil
+}
+
+const categoryColumns = `c.id, c.parent_id, c.name, c.description, c.created_at, c.updated_at`
+
+// ListCategories returns every category, ordered by name, with the number of
+// published articles filed directly under each.
+func (r *tagRepository) ListCategories(ctx context.Context) ([]model.Category, error) {
+	query := `SELECT ` + categoryColumns + `, COUNT(a.id)
+		FROM categories c
+		LEFT JOIN article_categories ac ON ac.category_id = c.id
+		LEFT JOIN articles a ON a.id = ac.article_id AND a.status = 'published'
+		GROUP BY c.id
+		ORDER BY c.name`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []model.Category{}
+	for rows.Next() {
+		var category model.Category
+		if err := rows.Scan(&category.ID, &category.ParentID, &category.Name, &category.Description,
+			&category.CreatedAt, &category.UpdatedAt, &category.ArticleCount); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+func (r *tagRepository) GetCategory(ctx context.Context, id string) (*model.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.id = $1`
+	var category model.Category
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&category.ID, &category.ParentID, &category.Name,
+		&category.Description, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *tagRepository) CreateCategory(ctx context.Context, category *model.Category) error {
+	query := `INSERT INTO categories (id, parent_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING created_at, updated_at`
+	err := r.db.QueryRowContext(ctx, query, category.ID, category.ParentID, category.Name, category.Description).
+		Scan(&category.CreatedAt, &category.UpdatedAt)
+	return categoryWriteError(err)
+}
+
+func (r *tagRepository) UpdateCategory(ctx context.Context, category *model.Category) error {
+	query := `UPDATE categories SET parent_id = $1, name = $2, description = $3, updated_at = NOW()
+		WHERE id = $4 RETURNING updated_at`
+	err := r.db.QueryRowContext(ctx, query, category.ParentID, category.Name, category.Description, category.ID).
+		Scan(&category.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("category not found")
+	}
+	return categoryWriteError(err)
+}
+
+// DeleteCategory removes a category that has no subcategories. Articles
+// filed under it simply lose that category.
+func (r *tagRepository) DeleteCategory(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("category has subcategories")
+		}
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return fmt.Errorf("category not found")
+	}
+	return nil
+}
+
+// IsDescendant reports whether id is rootID itself or one of its
+// descendants. Such a category cannot become the parent of rootID.
+func (r *tagRepository) IsDescendant(ctx context.Context, id, rootID string) (bool, error) {
+	query := `WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = $1
+			UNION ALL
+			SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+		)
+		SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`
+	var descendant bool
+	err := r.db.QueryRowContext(ctx, query, rootID, id).Scan(&descendant)
+	return descendant, err
+}
+
+func categoryWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return fmt.Errorf("category already exists")
+		case "23503":
+			return fmt.Errorf("parent category not found")
+		}
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"articlehub-api/internal/model"
+)
+
+// trendingRebuildInterval is how often a window's scores are recomputed from
+// scratch around a new epoch. This keeps the stored scores within the range
+// of a double and repairs what incremental updates miss, such as withdrawn
+// reactions.
+const trendingRebuildInterval = 24 * time.Hour
+
+// trendingEvents lists every piece of activity on an article with its time
+// and weight: a view counts 1 and a read 2 more, a reaction 5, a comment 8
+// and a bookmark 10. Views are only known per day and are placed at noon,
+// or now for the current morning. $1 is the current time.
+const trendingEvents = `(
+	SELECT article_id, created_at AS at, 5.0 AS weight FROM article_reactions
+	UNION ALL
+	SELECT article_id, created_at, 8.0 FROM comments WHERE deleted_at IS NULL
+	UNION ALL
+	SELECT article_id, added_at, 10.0 FROM bookmarks
+	UNION ALL
+	SELECT article_id, LEAST(day::timestamp AT TIME ZONE 'UTC' + INTERVAL '12 hours', $1::timestamptz), views + 2.0 * reads
+	FROM ` + dailyStats + ` s
+)`
+
+type TrendingRepository interface {
+	UpdateScores(ctx context.Context, window model.TrendingWindow, now time.Time) (int64, error)
+	GetTrending(ctx context.Context, window model.TrendingWindow, filter model.TrendingFilter, now time.Time) ([]model.TrendingArticle, error)
+}
+
+type trendingRepository struct {
+	db *sql.DB
+}
+
+func NewTrendingRepository(db *sql.DB) TrendingRepository {
+	return &trendingRepository{db: db}
+}
+
+// UpdateScores brings the scores of a window up to now. Only articles with
+// activity since the last update, or whose activity has since fallen out of
+// the window, are recomputed; the others keep their score, which stays
+// correct relative to the epoch. It returns the number of scores written.
+func (r *trendingRepository) UpdateScores(ctx context.Context, window model.TrendingWindow, now time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var epoch, since time.Time
+	query := `SELECT epoch, computed_at FROM trending_windows WHERE name = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, window.Name).Scan(&epoch, &since)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	if err == sql.ErrNoRows || now.Sub(epoch) >= trendingRebuildInterval {
+		// Starting over from the beginning of the window makes every
+		// article with activity in it dirty.
+		epoch, since = now, now.Add(-window.Length)
+		if _, err := tx.ExecContext(ctx, `DELETE FROM trending_scores WHERE window_name = $1`, window.Name); err != nil {
+			return 0, fmt.Errorf("failed to clear trending scores: %w", err)
+		}
+	}
+
+	// $1 now, $2 window, $3 window length and $4 half-life in seconds, $5
+	// epoch, $6 time of the last update.
+	query = `WITH events AS ` + trendingEvents + `,
+		dirty AS (
+			SELECT article_id FROM events
+			WHERE (at > $6::timestamptz AND at <= $1::timestamptz)
+				OR (at > $6::timestamptz - make_interval(secs => $3) AND at <= $1::timestamptz - make_interval(secs => $3))
+			UNION
+			SELECT article_id FROM article_visits
+			UNION
+			SELECT article_id FROM comments WHERE deleted_at > $6::timestamptz
+		),
+		scores AS (
+			SELECT e.article_id, SUM(e.weight * power(2, EXTRACT(EPOCH FROM e.at - $5::timestamptz) / $4::float8)) AS score
+			FROM events e JOIN articles a ON a.id = e.article_id AND a.status = 'published'
+			WHERE e.at > $1::timestamptz - make_interval(secs => $3) AND e.at <= $1::timestamptz
+				AND e.article_id IN (SELECT article_id FROM dirty)
+			GROUP BY e.article_id
+		),
+		stale AS (
+			DELETE FROM trending_scores
+			WHERE window_name = $2 AND article_id IN (SELECT article_id FROM dirty)
+				AND article_id NOT IN (SELECT article_id FROM scores)
+		)
+		INSERT INTO trending_scores (window_name, article_id, score)
+		SELECT $2, article_id, score FROM scores
+		ON CONFLICT (window_name, article_id) DO UPDATE SET score = EXCLUDED.score`
+	result, err := tx.ExecContext(ctx, query, now, window.Name, window.Length.Seconds(), window.HalfLife.Seconds(), epoch, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update trending scores: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	query = `INSERT INTO trending_windows (name, epoch, computed_at) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET epoch = EXCLUDED.epoch, computed_at = EXCLUDED.computed_at`
+	if _, err := tx.ExecContext(ctx, query, window.Name, epoch, now); err != nil {
+		return 0, err
+	}
+	return updated, tx.Commit()
+}
+
+// GetTrending returns the top published articles of a window, optionally
+// with a given tag, highest score first. Scores are decayed to now.
+func (r *trendingRepository) GetTrending(ctx context.Context, window model.TrendingWindow, filter model.TrendingFilter, now time.Time) ([]model.TrendingArticle, error) {
+	args := []any{window.Name, now, window.HalfLife.Seconds(), filter.Limit, filter.Offset}
+	tagFilter := ""
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		tagFilter = `AND articles.id IN (
+			SELECT article_id FROM article_tags WHERE tag_id = COALESCE(
+				(SELECT tag_id FROM tag_aliases WHERE alias = $6),
+				(SELECT id FROM tags WHERE name = $6)
+			)
+		)`
+	}
+
+	query := `SELECT ` + articleColumns + `,
+			ts.score * power(2, -EXTRACT(EPOCH FROM $2::timestamptz - w.epoch) / $3::float8)
+		FROM trending_scores ts
+		JOIN trending_windows w ON w.name = ts.window_name
+		JOIN articles ON articles.id = ts.article_id
+		WHERE ts.window_name = $1 AND articles.status = 'published' ` + tagFilter + `
+		ORDER BY ts.score DESC, articles.id
+		LIMIT $4 OFFSET $5`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	articles := []model.TrendingArticle{}
+	for rows.Next() {
+		var score float64
+		article, err := scanArticle(scoredRow{rows, &score})
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, model.TrendingArticle{Article: *article, Score: score})
+	}
+	return articles, rows.Err()
+}
+
+// scoredRow scans a row of articleColumns followed by a score.
+type scoredRow struct {
+	row   interface{ Scan(...any) error }
+	score *float64
+}
+
+func (r scoredRow) Scan(dest ...any) error {
+	return r.row.Scan(append(dest, r.score)...)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"articlehub-api/internal/model"
 )
@@ -12,9 +14,26 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *model.User) error
 	GetUsers(ctx context.Context) ([]model.User, error)
 	GetUserById(ctx context.Context, id string) (*model.User, error)
+	ResolveSlug(ctx context.Context, slug string) (string, error)
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	GetUserCredentials(ctx context.Context, id string) (*model.User, error)
 	UpdateUser(ctx context.Context, id string, user *model.User) error
-	DeleteUser(ctx context.Context, id string) error
+	DeactivateUser(ctx context.Context, id string) (time.Time, error)
+	RestoreUser(ctx context.Context, id string) error
+	PurgeDeactivatedUsers(ctx context.Context, deactivatedBefore time.Time, anonymize bool) (int64, error)
+	ListPurgedObjects(ctx context.Context, limit int) ([]model.PurgedObject, error)
+	DeletePurgedObjects(ctx context.Context, ids []int64) error
+	UpdatePassword(ctx context.Context, id, hash string) (int, error)
+
+	GetUserAuthState(ctx context.Context, id string) (*model.UserAuthState, error)
+	SearchUsers(ctx context.Context, filter model.UserFilter) ([]model.UserAccount, error)
+	GetUserAccount(ctx context.Context, id string) (*model.UserAccount, error)
+	GetUserStats(ctx context.Context, id string) (*model.UserStats, error)
+	SetUserRole(ctx context.Context, id, role string) error
+	SuspendUser(ctx context.Context, id, reason string) error
+	UnsuspendUser(ctx context.Context, id string) error
+	RequirePasswordReset(ctx context.Context, id string) error
+	RevokeSessions(ctx context.Context, id string) error
 }
 
 type userRepository struct {
@@ -26,16 +45,27 @@ func NewUserRepository(db *sql.DB) UserRepository {
 }
 
 func (r *userRepository) CreateUser(ctx context.Context, user *model.User) error {
-	query := `INSERT INTO users (id, name, email, password, created_at, updated_at) VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING id, created_at, updated_at`
-	err := r.db.QueryRowContext(ctx, query, user.ID, user.Name, user.Email, user.Password).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var slugBase string
+	user.Slug, slugBase, err = uniqueSlug(ctx, tx, userSlugs, user.ID, user.Name)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
-	return nil
+	query := `INSERT INTO users (id, name, slug, slug_base, email, password, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW()) RETURNING id, created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query, user.ID, user.Name, user.Slug, slugBase, user.Email, user.Password).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return tx.Commit()
 }
 
 func (r *userRepository) GetUsers(ctx context.Context) ([]model.User, error) {
-	query := `SELECT id, name, email, avatar_url, created_at, updated_at FROM users ORDER BY created_at DESC`
+	query := `SELECT id, name, slug, email, avatar_url, created_at, updated_at FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -45,7 +75,7 @@ func (r *userRepository) GetUsers(ctx context.Context) ([]model.User, error) {
 	var users []model.User
 	for rows.Next() {
 		var user model.User
-		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if err := rows.Scan(&user.ID, &user.Name, &user.Slug, &user.Email, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -54,10 +84,10 @@ func (r *userRepository) GetUsers(ctx context.Context) ([]model.User, error) {
 }
 
 func (r *userRepository) GetUserById(ctx context.Context, id string) (*model.User, error) {
-	query := `SELECT id, name, email, avatar_url, created_at, updated_at FROM users WHERE id = $1`
+	query := `SELECT id, name, slug, email, avatar_url, created_at, updated_at, version FROM users WHERE id = $1 AND deleted_at IS NULL`
 	var user model.User
 	err := r.db.QueryRowContext(ctx, query, id).
-		Scan(&user.ID, &user.Name, &user.Email, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt)
+		Scan(&user.ID, &user.Name, &user.Slug, &user.Email, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt, &user.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
@@ -67,10 +97,38 @@ func (r *userRepository) GetUserById(ctx context.Context, id string) (*model.Use
 	return &user, nil
 }
 
+// ResolveSlug returns the ID of the user currently or formerly known by slug.
+func (r *userRepository) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	id, err := resolveSlug(ctx, r.db, userSlugs, slug)
+	if err == nil && id == "" {
+		return "", fmt.Errorf("user not found")
+	}
+	return id, err
+}
+
 func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
-	query := `SELECT id, name, email, password, avatar_url, created_at, updated_at FROM users WHERE email = $1`
+	// Deactivated users are returned as well so that logging in during the
+	// grace period can restore the account.
+	query := `SELECT id, name, email, password, avatar_url, created_at, updated_at, deleted_at, suspended_at, password_reset_required,
+		session_generation
+		FROM users WHERE email = $1 AND purged_at IS NULL`
 	var user model.User
 	err := r.db.QueryRowContext(ctx, query, email).
+		Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt,
+			&user.DeletedAt, &user.SuspendedAt, &user.PasswordResetRequired, &user.SessionGeneration)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetUserCredentials(ctx context.Context, id string) (*model.User, error) {
+	query := `SELECT id, name, email, password, avatar_url, created_at, updated_at FROM users WHERE id = $1 AND deleted_at IS NULL`
+	var user model.User
+	err := r.db.QueryRowContext(ctx, query, id).
 		Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.AvatarURL, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -81,15 +139,313 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	return &user, nil
 }
 
+// UpdateUser saves the user if it is still at user.Version, and reports
+// "version mismatch" when someone else updated it in the meantime. A new name
+// moves the user to a new slug; the old one keeps resolving.
 func (r *userRepository) UpdateUser(ctx context.Context, id string, user *model.User) error {
-	query := `UPDATE users SET name = $1, email = $2, avatar_url = $3, updated_at = NOW() WHERE id = $4 RETURNING updated_at`
-	return r.db.QueryRowContext(ctx, query, user.Name, user.Email, user.AvatarURL, id).
-		Scan(&user.UpdatedAt)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var slug, slugBase string
+	query := `UPDATE users SET name = $1, email = $2, avatar_url = $3, updated_at = NOW(), version = version + 1
+		WHERE id = $4 AND version = $5 RETURNING updated_at, version, slug, COALESCE(slug_base, '')`
+	err = tx.QueryRowContext(ctx, query, user.Name, user.Email, user.AvatarURL, id, user.Version).
+		Scan(&user.UpdatedAt, &user.Version, &slug, &slugBase)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("version mismatch")
+	}
+	if err != nil {
+		return err
+	}
+	if user.Slug, err = renameSlug(ctx, tx, userSlugs, id, slug, slugBase, user.Name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *userRepository) DeactivateUser(ctx context.Context, id string) (time.Time, error) {
+	query := `UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING deleted_at`
+	var deletedAt time.Time
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&deletedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, fmt.Errorf("user not found")
+		}
+		return time.Time{}, err
+	}
+	return deletedAt, nil
+}
+
+func (r *userRepository) RestoreUser(ctx context.Context, id string) error {
+	query := `UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL AND purged_at IS NULL`
+	return r.execUser(ctx, query, id)
+}
+
+// PurgeDeactivatedUsers permanently removes accounts deactivated before the
+// given time. When anonymize is set the rows are kept, so that references to
+// them stay valid, but every piece of personal data is scrubbed instead.
+// Either way the media library goes, and the stored files of the library
+// and the avatar are queued for removal from storage in the same statement.
+func (r *userRepository) PurgeDeactivatedUsers(ctx context.Context, deactivatedBefore time.Time, anonymize bool) (int64, error) {
+	purge := `DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING id, avatar_url`
+	if anonymize {
+		// The avatar URL is read before it is cleared. Slugs are derived
+		// from names, so they go as well.
+		purge = `WITH expired AS (
+				SELECT id, avatar_url FROM users
+				WHERE deleted_at IS NOT NULL AND deleted_at < $1 AND purged_at IS NULL
+				FOR UPDATE
+			), updated AS (
+				UPDATE users SET
+					name = 'Deleted user',
+					slug = 'deleted-user-' || id,
+					email = 'deleted-' || id || '@invalid',
+					password = '',
+					avatar_url = '',
+					purged_at = NOW(),
+					updated_at = NOW()
+				WHERE id IN (SELECT id FROM expired)
+			), history AS (
+				DELETE FROM user_slug_history WHERE user_id IN (SELECT id FROM expired)
+			)
+			SELECT id, avatar_url FROM expired`
+	}
+	// Deleted users would take their media with them, but anonymized ones
+	// would not, so the library is deleted explicitly in both cases.
+	query := `WITH purged AS (` + purge + `), media AS (
+			DELETE FROM media WHERE owner_id IN (SELECT id FROM purged) RETURNING storage_keys
+		), objects AS (
+			INSERT INTO purged_objects (storage_key, url)
+			SELECT unnest(storage_keys), '' FROM media
+			UNION ALL
+			SELECT '', avatar_url FROM purged WHERE avatar_url <> ''
+		)
+		SELECT COUNT(*) FROM purged`
+	var purged int64
+	if err := r.db.QueryRowContext(ctx, query, deactivatedBefore).Scan(&purged); err != nil {
+		return 0, fmt.Errorf("failed to purge users: %w", err)
+	}
+	return purged, nil
+}
+
+// ListPurgedObjects returns up to limit stored objects of purged accounts
+// that are still to be removed from storage, oldest first.
+func (r *userRepository) ListPurgedObjects(ctx context.Context, limit int) ([]model.PurgedObject, error) {
+	query := `SELECT id, storage_key, url FROM purged_objects ORDER BY id LIMIT $1`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []model.PurgedObject
+	for rows.Next() {
+		var object model.PurgedObject
+		if err := rows.Scan(&object.ID, &object.Key, &object.URL); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	return objects, rows.Err()
+}
+
+// DeletePurgedObjects clears objects that have been removed from storage.
+func (r *userRepository) DeletePurgedObjects(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `DELETE FROM purged_objects WHERE id = ANY($1)`, ids)
+	return err
+}
+
+// revokeSessions is the assignment that signs a user out everywhere: tokens
+// of earlier generations are no longer accepted.
+const revokeSessions = `session_generation = session_generation + 1, tokens_valid_after = NOW()`
+
+// UpdatePassword stores a new password hash, clears any forced reset and
+// signs out every existing session. It returns the new session generation,
+// which the replacement token must carry.
+func (r *userRepository) UpdatePassword(ctx context.Context, id, hash string) (int, error) {
+	query := `UPDATE users SET password = $1, password_reset_required = FALSE, ` + revokeSessions + `, updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL RETURNING session_generation`
+	var generation int
+	err := r.db.QueryRowContext(ctx, query, hash, id).Scan(&generation)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("user not found")
+		}
+		return 0, err
+	}
+	return generation, nil
+}
+
+func (r *userRepository) GetUserAuthState(ctx context.Context, id string) (*model.UserAuthState, error) {
+	query := `SELECT id, role, suspended_at IS NOT NULL, password_reset_required, session_generation
+		FROM users WHERE id = $1 AND deleted_at IS NULL`
+	var state model.UserAuthState
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&state.ID, &state.Role, &state.Suspended, &state.PasswordResetRequired, &state.SessionGeneration)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+const userAccountColumns = `id, name, email, avatar_url, role, suspended_at, suspension_reason, password_reset_required,
+	tokens_valid_after, deleted_at, created_at, updated_at`
+
+func scanUserAccount(row interface{ Scan(...any) error }) (*model.UserAccount, error) {
+	var account model.UserAccount
+	err := row.Scan(&account.ID, &account.Name, &account.Email, &account.AvatarURL, &account.Role,
+		&account.SuspendedAt, &account.SuspensionReason, &account.PasswordResetRequired,
+		&account.TokensValidAfter, &account.DeactivatedAt, &account.CreatedAt, &account.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// SearchUsers lists accounts for administrators, including deactivated ones
+// that have not been purged yet. Query matches name or email.
+func (r *userRepository) SearchUsers(ctx context.Context, filter model.UserFilter) ([]model.UserAccount, error) {
+	conditions := []string{"purged_at IS NULL"}
+	var args []any
+	if filter.Query != "" {
+		args = append(args, "%"+filter.Query+"%")
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR email ILIKE $%d)", len(args), len(args)))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+	switch filter.Status {
+	case "active":
+		conditions = append(conditions, "deleted_at IS NULL AND suspended_at IS NULL")
+	case "suspended":
+		conditions = append(conditions, "suspended_at IS NOT NULL")
+	case "deactivated":
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		userAccountColumns, strings.Join(conditions, " AND "), len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []model.UserAccount{}
+	for rows.Next() {
+		account, err := scanUserAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *userRepository) GetUserAccount(ctx context.Context, id string) (*model.UserAccount, error) {
+	query := `SELECT ` + userAccountColumns + ` FROM users WHERE id = $1 AND purged_at IS NULL`
+	account, err := scanUserAccount(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+func (r *userRepository) GetUserStats(ctx context.Context, id string) (*model.UserStats, error) {
+	if _, err := r.GetUserAccount(ctx, id); err != nil {
+		return nil, err
+	}
+
+	stats := &model.UserStats{UserID: id, Actions: map[string]int64{}}
+
+	query := `SELECT action, COUNT(*), MAX(created_at) FROM audit_logs
+		WHERE actor_id = $1 OR (target_type = 'user' AND target_id = $2)
+		GROUP BY action`
+	rows, err := r.db.QueryContext(ctx, query, id, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			action string
+			count  int64
+			last   time.Time
+		)
+		if err := rows.Scan(&action, &count, &last); err != nil {
+			return nil, err
+		}
+		stats.Actions[action] = count
+		switch action {
+		case model.AuditActionUserLogin:
+			stats.Logins = count
+			stats.LastLoginAt = &last
+		case model.AuditActionUserLoginFailed:
+			stats.FailedLogins = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT COUNT(*) FROM data_exports WHERE user_id = $1`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&stats.DataExports); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *userRepository) SetUserRole(ctx context.Context, id, role string) error {
+	query := `UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2 AND purged_at IS NULL`
+	return r.execUser(ctx, query, role, id)
+}
+
+func (r *userRepository) SuspendUser(ctx context.Context, id, reason string) error {
+	query := `UPDATE users SET suspended_at = NOW(), suspension_reason = $1, updated_at = NOW()
+		WHERE id = $2 AND suspended_at IS NULL AND purged_at IS NULL`
+	return r.execUser(ctx, query, reason, id)
+}
+
+func (r *userRepository) UnsuspendUser(ctx context.Context, id string) error {
+	query := `UPDATE users SET suspended_at = NULL, suspension_reason = '', updated_at = NOW()
+		WHERE id = $1 AND suspended_at IS NOT NULL AND purged_at IS NULL`
+	return r.execUser(ctx, query, id)
+}
+
+// RequirePasswordReset signs the user out everywhere and forces them to
+// choose a new password on their next login.
+func (r *userRepository) RequirePasswordReset(ctx context.Context, id string) error {
+	query := `UPDATE users SET password_reset_required = TRUE, ` + revokeSessions + `, updated_at = NOW()
+		WHERE id = $1 AND purged_at IS NULL`
+	return r.execUser(ctx, query, id)
+}
+
+// RevokeSessions invalidates every token issued to the user so far.
+func (r *userRepository) RevokeSessions(ctx context.Context, id string) error {
+	query := `UPDATE users SET ` + revokeSessions + ` WHERE id = $1 AND purged_at IS NULL`
+	return r.execUser(ctx, query, id)
 }
 
-func (r *userRepository) DeleteUser(ctx context.Context, id string) error {
-	query := `DELETE FROM users WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+// execUser runs an update against a single user and reports "user not found"
+// when no row matched.
+func (r *userRepository) execUser(ctx context.Context, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,116 @@
+// Package seo builds the metadata search engines and social networks read
+// from article pages, and the sitemap and robots.txt that drive crawling.
+package seo
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"articlehub-api/internal/content"
+	"articlehub-api/internal/model"
+)
+
+const descriptionLength = 160
+
+// Site is the public face of the application that generated URLs point to.
+type Site struct {
+	// URL has no trailing slash.
+	URL   string
+	Title string
+}
+
+func (s Site) ArticleURL(slug string) string {
+	return s.URL + "/articles/" + url.PathEscape(slug)
+}
+
+// Article returns the metadata of an article page. Unpublished articles are
+// marked noindex.
+func (s Site) Article(article *model.Article) *model.SEOMetadata {
+	canonical := s.ArticleURL(article.Slug)
+	description := content.Summary(article.BodyHTML, descriptionLength)
+	published := article.CreatedAt
+	if article.PublishedAt != nil {
+		published = *article.PublishedAt
+	}
+
+	meta := &model.SEOMetadata{
+		CanonicalURL: canonical,
+		Title:        article.Title + " | " + s.Title,
+		Description:  description,
+		Robots:       "index, follow",
+		OpenGraph: []model.MetaTag{
+			{Name: "og:type", Content: "article"},
+			{Name: "og:url", Content: canonical},
+			{Name: "og:title", Content: article.Title},
+			{Name: "og:description", Content: description},
+			{Name: "og:site_name", Content: s.Title},
+			{Name: "article:published_time", Content: published.UTC().Format(time.RFC3339)},
+			{Name: "article:modified_time", Content: article.UpdatedAt.UTC().Format(time.RFC3339)},
+		},
+		TwitterCard: []model.MetaTag{
+			{Name: "twitter:card", Content: "summary"},
+			{Name: "twitter:title", Content: article.Title},
+			{Name: "twitter:description", Content: description},
+		},
+	}
+	if article.Status != model.ArticleStatusPublished {
+		meta.Robots = "noindex, nofollow"
+	}
+	if article.AuthorName != "" {
+		meta.OpenGraph = append(meta.OpenGraph, model.MetaTag{Name: "article:author", Content: article.AuthorName})
+	}
+	for _, tag := range article.Tags {
+		meta.OpenGraph = append(meta.OpenGraph, model.MetaTag{Name: "article:tag", Content: tag})
+	}
+	if cover := article.Cover; cover != nil {
+		meta.OpenGraph = append(meta.OpenGraph,
+			model.MetaTag{Name: "og:image", Content: cover.URL},
+			model.MetaTag{Name: "og:image:width", Content: strconv.Itoa(cover.Width)},
+			model.MetaTag{Name: "og:image:height", Content: strconv.Itoa(cover.Height)},
+		)
+		meta.TwitterCard[0].Content = "summary_large_image"
+		meta.TwitterCard = append(meta.TwitterCard, model.MetaTag{Name: "twitter:image", Content: cover.URL})
+		if cover.Alt != "" {
+			meta.OpenGraph = append(meta.OpenGraph, model.MetaTag{Name: "og:image:alt", Content: cover.Alt})
+			meta.TwitterCard = append(meta.TwitterCard, model.MetaTag{Name: "twitter:image:alt", Content: cover.Alt})
+		}
+	}
+
+	ld := map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            "Article",
+		"headline":         headline(article.Title),
+		"description":      description,
+		"url":              canonical,
+		"mainEntityOfPage": map[string]any{"@type": "WebPage", "@id": canonical},
+		"datePublished":    published.UTC().Format(time.RFC3339),
+		"dateModified":     article.UpdatedAt.UTC().Format(time.RFC3339),
+		"publisher":        map[string]any{"@type": "Organization", "name": s.Title, "url": s.URL},
+	}
+	if article.AuthorName != "" {
+		ld["author"] = map[string]any{"@type": "Person", "name": article.AuthorName}
+	}
+	if len(article.Tags) > 0 {
+		ld["keywords"] = article.Tags
+	}
+	if article.Cover != nil {
+		ld["image"] = map[string]any{
+			"@type":  "ImageObject",
+			"url":    article.Cover.URL,
+			"width":  article.Cover.Width,
+			"height": article.Cover.Height,
+		}
+	}
+	meta.JSONLD = ld
+	return meta
+}
+
+// headline shortens a title to the 110 characters search engines display
+// for an Article headline.
+func headline(title string) string {
+	if runes := []rune(title); len(runes) > 110 {
+		return string(runes[:109]) + "…"
+	}
+	return title
+}
@@ -0,0 +1,94 @@
+package seo
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSitemapURLs is the number of URLs the sitemap protocol allows in a
+// single file. Larger sitemaps are split and listed in a sitemap index.
+const MaxSitemapURLs = 50000
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	NS      string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	NS       string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// URL is one location listed in a sitemap.
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// URLSet encodes a sitemap listing urls.
+func URLSet(urls []URL) ([]byte, error) {
+	doc := urlSet{NS: sitemapNS, URLs: []sitemapURL{}}
+	for _, u := range urls {
+		doc.URLs = append(doc.URLs, sitemapURL{Loc: u.Loc, LastMod: lastMod(u.LastMod)})
+	}
+	return marshal(doc)
+}
+
+// Index encodes a sitemap index pointing to the given sitemaps.
+func Index(sitemaps []URL) ([]byte, error) {
+	doc := sitemapIndex{NS: sitemapNS}
+	for _, u := range sitemaps {
+		doc.Sitemaps = append(doc.Sitemaps, sitemapURL{Loc: u.Loc, LastMod: lastMod(u.LastMod)})
+	}
+	return marshal(doc)
+}
+
+// PageURL is the location of page n of a split sitemap.
+func (s Site) PageURL(n int) string {
+	return s.URL + "/sitemaps/" + strconv.Itoa(n) + ".xml"
+}
+
+// Robots renders robots.txt. With indexing disabled every crawler is kept
+// out of the whole site; otherwise only the disallowed path prefixes are.
+func (s Site) Robots(indexing bool, disallow []string) string {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if !indexing {
+		b.WriteString("Disallow: /\n")
+		return b.String()
+	}
+	if len(disallow) == 0 {
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range disallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\nSitemap: " + s.URL + "/sitemap.xml\n")
+	return b.String()
+}
+
+func lastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func marshal(doc any) ([]byte, error) {
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
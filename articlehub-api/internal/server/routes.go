@@ -1,6 +1,8 @@
 package server
 
 import (
+	"strings"
+
 	"articlehub-api/internal/middleware"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,21 +14,152 @@ func (s *FiberServer) RegisterFiberRoutes() {
 	s.App.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS,PATCH",
-		AllowHeaders:     "Accept,Authorization,Content-Type",
+		AllowHeaders:     "Accept,Authorization,Content-Type,If-Match,If-None-Match,If-Modified-Since",
+		ExposeHeaders:    "ETag,Last-Modified," + middleware.HeaderImpersonatedBy,
 		AllowCredentials: false, // credentials require explicit origins
 		MaxAge:           300,
 	}))
 
+	s.App.Use(middleware.LimitBody(s.bodyLimit, isUpload))
+
 	s.App.Get("/", s.HelloWorldHandler)
 	s.App.Get("/health", s.healthHandler)
+	s.App.Get("/robots.txt", s.seoHandler.Robots)
+	s.App.Get("/sitemap.xml", s.seoHandler.Sitemap)
+	s.App.Get("/sitemaps/:page.xml", s.seoHandler.SitemapPage)
+	s.App.Get("/styles/highlight.css", s.styleHandler.Highlight)
+
+	authenticated := middleware.Middleware(s.db.UserRepo(), s.audit)
+	optionalAuth := middleware.Optional(s.db.UserRepo(), s.audit)
+	notImpersonated := middleware.BlockImpersonation()
 
 	users := s.App.Group("/users")
 	users.Post("/", s.handler.CreateUser)
 	users.Get("/", s.handler.GetUsers)
 	users.Post("/login", s.handler.Login)
+
+	exports := users.Group("/me/exports", authenticated, notImpersonated)
+	exports.Post("/", s.exportHandler.CreateExport)
+	exports.Get("/:id", s.exportHandler.GetExport)
+	exports.Get("/:id/download", s.exportHandler.DownloadExport)
+	users.Get("/me/tags", authenticated, s.followHandler.ListFollowedTags)
+	users.Get("/me/stats", authenticated, s.analyticsHandler.GetStats)
+	users.Get("/me/media", authenticated, s.mediaHandler.ListMedia)
+
+	bookmarks := users.Group("/me/bookmarks", authenticated)
+	bookmarks.Get("/", s.readingListHandler.ListBookmarks)
+	bookmarks.Put("/:articleId", s.readingListHandler.SaveBookmark)
+	bookmarks.Delete("/:articleId", s.readingListHandler.DeleteBookmark)
+
+	lists := users.Group("/me/lists", authenticated)
+	lists.Get("/", s.readingListHandler.ListReadingLists)
+	lists.Post("/", s.readingListHandler.CreateReadingList)
+	lists.Get("/:id", s.readingListHandler.GetReadingList)
+	lists.Put("/:id", s.readingListHandler.UpdateReadingList)
+	lists.Delete("/:id", s.readingListHandler.DeleteReadingList)
+	lists.Put("/:id/order", s.readingListHandler.ReorderItems)
+	lists.Put("/:id/items/:articleId", s.readingListHandler.SaveItem)
+	lists.Delete("/:id/items/:articleId", s.readingListHandler.RemoveItem)
+
 	users.Get("/:id", s.handler.GetUserById)
-	users.Put("/:id", middleware.Middleware(), s.handler.UpdateUser)
-	users.Delete("/:id", s.handler.DeleteUser)
+	users.Put("/:id", authenticated, notImpersonated, s.handler.UpdateUser)
+	users.Put("/:id/password", middleware.AllowPasswordReset(s.db.UserRepo(), s.audit), notImpersonated, s.handler.ChangePassword)
+	users.Delete("/:id", authenticated, notImpersonated, s.handler.DeleteUser)
+	users.Get("/:id/lists", s.readingListHandler.ListPublicReadingLists)
+	users.Get("/:id/followers", s.followHandler.ListFollowers)
+	users.Get("/:id/following", s.followHandler.ListFollowing)
+	users.Put("/:id/follow", authenticated, s.followHandler.FollowUser)
+	users.Delete("/:id/follow", authenticated, s.followHandler.UnfollowUser)
+
+	s.App.Get("/feed", authenticated, s.followHandler.GetFeed)
+	s.App.Get("/lists/:token", optionalAuth, s.readingListHandler.GetSharedReadingList)
+
+	articles := s.App.Group("/articles")
+	articles.Get("/", optionalAuth, s.articleHandler.GetArticles)
+	articles.Post("/", authenticated, s.articleHandler.CreateArticle)
+	articles.Get("/trending", s.trendingHandler.GetTrending)
+	articles.Get("/:id", optionalAuth, s.articleHandler.GetArticleById)
+	articles.Put("/:id", authenticated, s.articleHandler.UpdateArticle)
+	articles.Delete("/:id", authenticated, s.articleHandler.DeleteArticle)
+	articles.Get("/:id/related", optionalAuth, s.relatedHandler.GetRelated)
+	articles.Get("/:id/transitions", authenticated, s.articleHandler.GetTransitions)
+	articles.Post("/:id/transitions", authenticated, s.articleHandler.TransitionArticle)
+	articles.Get("/:id/revisions", authenticated, s.articleHandler.GetRevisions)
+	articles.Get("/:id/revisions/diff", authenticated, s.articleHandler.DiffRevisions)
+	articles.Get("/:id/revisions/:number", authenticated, s.articleHandler.GetRevision)
+	articles.Post("/:id/revisions/:number/restore", authenticated, s.articleHandler.RestoreRevision)
+	articles.Get("/:id/comments", optionalAuth, s.commentHandler.ListComments)
+	articles.Post("/:id/comments", authenticated, s.commentHandler.CreateComment)
+	articles.Get("/:id/comments/:commentId/replies", optionalAuth, s.commentHandler.ListReplies)
+	articles.Put("/:id/comments/:commentId", authenticated, s.commentHandler.UpdateComment)
+	articles.Delete("/:id/comments/:commentId", authenticated, s.commentHandler.DeleteComment)
+	articles.Post("/:id/views", optionalAuth, s.analyticsHandler.RecordView)
+	articles.Post("/:id/reads", optionalAuth, s.analyticsHandler.RecordRead)
+	articles.Get("/:id/reactions", optionalAuth, s.reactionHandler.GetReactions)
+	articles.Put("/:id/reactions/:reaction", authenticated, s.reactionHandler.SetReaction)
+	articles.Delete("/:id/reactions", authenticated, s.reactionHandler.RemoveReaction)
+	articles.Get("/:id/comments/:commentId/reactions", optionalAuth, s.reactionHandler.GetReactions)
+	articles.Put("/:id/comments/:commentId/reactions/:reaction", authenticated, s.reactionHandler.SetReaction)
+	articles.Delete("/:id/comments/:commentId/reactions", authenticated, s.reactionHandler.RemoveReaction)
+
+	s.App.Get("/tags", s.tagHandler.ListTags)
+	s.App.Get("/reactions", s.reactionHandler.ListReactionTypes)
+	s.App.Put("/tags/:tag/follow", authenticated, s.followHandler.FollowTag)
+	s.App.Delete("/tags/:tag/follow", authenticated, s.followHandler.UnfollowTag)
+	s.App.Get("/categories", s.tagHandler.ListCategories)
+
+	media := s.App.Group("/media", authenticated)
+	media.Post("/", s.mediaHandler.UploadMedia)
+	media.Get("/:id", s.mediaHandler.GetMedia)
+	media.Put("/:id", s.mediaHandler.UpdateMedia)
+	media.Delete("/:id", s.mediaHandler.DeleteMedia)
+
+	series := s.App.Group("/series")
+	series.Get("/", optionalAuth, s.seriesHandler.ListSeries)
+	series.Post("/", authenticated, s.seriesHandler.CreateSeries)
+	series.Get("/:id", optionalAuth, s.seriesHandler.GetSeries)
+	series.Put("/:id", authenticated, s.seriesHandler.UpdateSeries)
+	series.Delete("/:id", authenticated, s.seriesHandler.DeleteSeries)
+	series.Put("/:id/order", authenticated, s.seriesHandler.ReorderArticles)
+	series.Put("/:id/articles/:articleId", authenticated, s.seriesHandler.AddArticle)
+	series.Delete("/:id/articles/:articleId", authenticated, s.seriesHandler.RemoveArticle)
+
+	feeds := s.App.Group("/feeds")
+	feeds.Get("/authors/:id/:format", s.syndicationHandler.AuthorFeed)
+	feeds.Get("/tags/:tag/:format", s.syndicationHandler.TagFeed)
+	feeds.Get("/:format", s.syndicationHandler.SiteFeed)
+
+	admin := s.App.Group("/admin", authenticated, middleware.RequireAdmin())
+	admin.Get("/audit-logs", s.auditHandler.ListEntries)
+	admin.Get("/users", s.adminHandler.ListUsers)
+	admin.Get("/users/:id", s.adminHandler.GetUser)
+	admin.Get("/users/:id/stats", s.adminHandler.GetUserStats)
+	admin.Put("/users/:id/role", s.adminHandler.ChangeRole)
+	admin.Post("/users/:id/suspend", s.adminHandler.SuspendUser)
+	admin.Post("/users/:id/unsuspend", s.adminHandler.UnsuspendUser)
+	admin.Post("/users/:id/force-password-reset", s.adminHandler.ForcePasswordReset)
+	admin.Post("/users/:id/revoke-sessions", s.adminHandler.RevokeSessions)
+	admin.Post("/users/:id/impersonate", s.adminHandler.Impersonate)
+	admin.Get("/tags/aliases", s.tagHandler.ListAliases)
+	admin.Post("/tags/aliases", s.tagHandler.CreateAlias)
+	admin.Delete("/tags/aliases/:alias", s.tagHandler.DeleteAlias)
+	admin.Post("/categories", s.tagHandler.CreateCategory)
+	admin.Put("/categories/:id", s.tagHandler.UpdateCategory)
+	admin.Delete("/categories/:id", s.tagHandler.DeleteCategory)
+}
+
+// isUpload reports whether a request goes to one of the routes that take a
+// file, PUT /users/:id and POST /media.
+func isUpload(c *fiber.Ctx) bool {
+	path := strings.Trim(c.Path(), "/")
+	switch c.Method() {
+	case fiber.MethodPut:
+		segments := strings.Split(path, "/")
+		return len(segments) == 2 && segments[0] == "users"
+	case fiber.MethodPost:
+		return path == "media"
+	}
+	return false
 }
 
 func (s *FiberServer) HelloWorldHandler(c *fiber.Ctx) error {
@@ -1,32 +1,207 @@
 package server
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 
+	"articlehub-api/internal/audit"
+	"articlehub-api/internal/config"
+	"articlehub-api/internal/content"
 	"articlehub-api/internal/database"
 	"articlehub-api/internal/handler"
+	"articlehub-api/internal/images"
+	"articlehub-api/internal/jobs"
+	"articlehub-api/internal/seo"
+	"articlehub-api/internal/storage"
 )
 
 type FiberServer struct {
 	*fiber.App
 
-	db      database.Service
-	handler *handler.UserHandler
+	// bodyLimit caps request bodies, except those of uploads which have
+	// limits of their own.
+	bodyLimit int
+
+	db                 database.Service
+	audit              *audit.Service
+	handler            *handler.UserHandler
+	exportHandler      *handler.ExportHandler
+	auditHandler       *handler.AuditHandler
+	adminHandler       *handler.AdminHandler
+	articleHandler     *handler.ArticleHandler
+	tagHandler         *handler.TagHandler
+	commentHandler     *handler.CommentHandler
+	reactionHandler    *handler.ReactionHandler
+	followHandler      *handler.FollowHandler
+	readingListHandler *handler.ReadingListHandler
+	seriesHandler      *handler.SeriesHandler
+	syndicationHandler *handler.SyndicationHandler
+	seoHandler         *handler.SEOHandler
+	styleHandler       *handler.StyleHandler
+	analyticsHandler   *handler.AnalyticsHandler
+	trendingHandler    *handler.TrendingHandler
+	relatedHandler     *handler.RelatedHandler
+	mediaHandler       *handler.MediaHandler
+
+	accountPurger      *jobs.AccountPurger
+	dataExporter       *jobs.DataExporter
+	auditRetention     *jobs.AuditRetention
+	publishScheduler   *jobs.PublishScheduler
+	revisionPruner     *jobs.RevisionPruner
+	reactionReconciler *jobs.ReactionReconciler
+	statsBackfill      *jobs.ArticleStatsBackfill
+	analyticsRollup    *jobs.AnalyticsRollup
+	trendingRanker     *jobs.TrendingRanker
+	relatedArticles    *jobs.RelatedArticles
 }
 
 func New() *FiberServer {
 	db := database.New()
-	userHandler := handler.NewUserHandler(db.UserRepo())
+	auditService := audit.NewService(db.AuditRepo())
+	gracePeriod := config.Duration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour)
+	store := storage.NewSupabase(config.String("S3_BUCKET_ENDPOINT", ""), config.String("S3_BUCKET_NAME", ""),
+		config.String("S3_BUCKET_SERVICE_ROLE", ""))
+	decoder := images.NewDecoder(config.Int("IMAGE_DECODE_CONCURRENCY", 2))
+	userHandler := handler.NewUserHandler(db.UserRepo(), gracePeriod, auditService, store,
+		int64(config.Int("AVATAR_MAX_SIZE", 2<<20)), decoder)
+	renderer := content.NewRenderer()
+	site := seo.Site{
+		URL:   strings.TrimRight(config.String("SITE_URL", "http://localhost:8080"), "/"),
+		Title: config.String("SITE_TITLE", "ArticleHub"),
+	}
+
+	bodyLimit := config.Int("REQUEST_BODY_LIMIT", 4<<20)
 
 	server := &FiberServer{
 		App: fiber.New(fiber.Config{
 			ServerHeader: "articlehub-api",
 			AppName:      "articlehub-api",
+			// Bodies larger than BodyLimit are streamed to the handler
+			// rather than buffered, and multipart forms are left unparsed,
+			// so uploads can be passed on to storage as they arrive.
+			BodyLimit:                    bodyLimit,
+			StreamRequestBody:            true,
+			DisablePreParseMultipartForm: true,
+			// The client address is only taken from ProxyHeader on
+			// connections from a trusted proxy, otherwise anyone could
+			// pick the address their visits and audit entries are
+			// recorded under.
+			ProxyHeader:             config.String("PROXY_HEADER", ""),
+			EnableTrustedProxyCheck: true,
+			TrustedProxies:          config.List("TRUSTED_PROXIES", nil),
+			EnableIPValidation:      true,
 		}),
+		bodyLimit: bodyLimit,
+
+		db:            db,
+		audit:         auditService,
+		handler:       userHandler,
+		exportHandler: handler.NewExportHandler(db.ExportRepo(), auditService),
+		auditHandler:  handler.NewAuditHandler(db.AuditRepo()),
+		adminHandler: handler.NewAdminHandler(db.UserRepo(), auditService,
+			config.Duration("IMPERSONATION_TOKEN_TTL", time.Hour)),
+		articleHandler: handler.NewArticleHandler(db.ArticleRepo(), db.SeriesRepo(), db.MediaRepo(), renderer, site),
+		tagHandler:     handler.NewTagHandler(db.TagRepo()),
+		commentHandler: handler.NewCommentHandler(db.CommentRepo(), db.ArticleRepo(), renderer,
+			config.Int("COMMENT_MAX_DEPTH", 5), config.Int("COMMENT_THREAD_REPLIES", 20)),
+		reactionHandler:    handler.NewReactionHandler(db.ReactionRepo(), db.ArticleRepo(), db.CommentRepo()),
+		followHandler:      handler.NewFollowHandler(db.FollowRepo(), db.UserRepo()),
+		readingListHandler: handler.NewReadingListHandler(db.ReadingListRepo(), db.ArticleRepo(), db.UserRepo()),
+		seriesHandler:      handler.NewSeriesHandler(db.SeriesRepo(), db.ArticleRepo()),
+		syndicationHandler: handler.NewSyndicationHandler(db.ArticleRepo(), db.UserRepo(), site.URL, site.Title,
+			config.Int("FEED_ITEM_LIMIT", 20),
+			config.Duration("FEED_CACHE_MAX_AGE", 5*time.Minute)),
+		seoHandler: handler.NewSEOHandler(db.ArticleRepo(), site,
+			config.Bool("ROBOTS_ALLOW_INDEXING", true),
+			config.List("ROBOTS_DISALLOW", []string{"/admin/", "/users/me/"}),
+			config.Duration("SITEMAP_CACHE_MAX_AGE", time.Hour)),
+		styleHandler: handler.NewStyleHandler(config.String("HIGHLIGHT_THEME", "github"),
+			config.Duration("STYLESHEET_CACHE_MAX_AGE", 24*time.Hour)),
+		analyticsHandler: handler.NewAnalyticsHandler(db.AnalyticsRepo(), db.ArticleRepo(), site.URL,
+			config.Int("STATS_MAX_RANGE_DAYS", 366)),
+		trendingHandler: handler.NewTrendingHandler(db.TrendingRepo()),
+		relatedHandler:  handler.NewRelatedHandler(db.RelatedRepo(), db.ArticleRepo()),
+		mediaHandler: handler.NewMediaHandler(db.MediaRepo(), store,
+			config.IntList("MEDIA_VARIANT_WIDTHS", []int{320, 640, 960, 1280, 1920}),
+			int64(config.Int("MEDIA_MAX_SIZE", 10<<20)), decoder),
 
-		db:      db,
-		handler: userHandler,
+		accountPurger: &jobs.AccountPurger{
+			Repo:        db.UserRepo(),
+			Storage:     store,
+			GracePeriod: gracePeriod,
+			Interval:    config.Duration("ACCOUNT_PURGE_INTERVAL", time.Hour),
+			Anonymize:   config.String("ACCOUNT_PURGE_MODE", "delete") == "anonymize",
+		},
+		dataExporter: &jobs.DataExporter{
+			Users:   db.UserRepo(),
+			Exports: db.ExportRepo(),
+			Sources: []jobs.ExportSource{
+				&jobs.ArticleExport{Repo: db.ArticleRepo()},
+				&jobs.MediaExport{Repo: db.MediaRepo()},
+				&jobs.CommentExport{Repo: db.CommentRepo()},
+				&jobs.ReadingListExport{Repo: db.ReadingListRepo()},
+				&jobs.SeriesExport{Repo: db.SeriesRepo()},
+			},
+			Dir:      config.String("DATA_EXPORT_DIR", "exports"),
+			TTL:      config.Duration("DATA_EXPORT_TTL", 7*24*time.Hour),
+			Interval: config.Duration("DATA_EXPORT_INTERVAL", 30*time.Second),
+		},
+		auditRetention: &jobs.AuditRetention{
+			Repo:      db.AuditRepo(),
+			Retention: config.Duration("AUDIT_LOG_RETENTION", 365*24*time.Hour),
+			Interval:  config.Duration("AUDIT_LOG_PRUNE_INTERVAL", 24*time.Hour),
+		},
+		publishScheduler: &jobs.PublishScheduler{
+			Repo:     db.ArticleRepo(),
+			Interval: config.Duration("PUBLISH_SCHEDULER_INTERVAL", time.Minute),
+		},
+		revisionPruner: &jobs.RevisionPruner{
+			Repo:     db.ArticleRepo(),
+			Keep:     config.Int("REVISION_MAX_PER_ARTICLE", 0),
+			MaxAge:   config.Duration("REVISION_MAX_AGE", 0),
+			Interval: config.Duration("REVISION_PRUNE_INTERVAL", 24*time.Hour),
+		},
+		reactionReconciler: &jobs.ReactionReconciler{
+			Repo:     db.ReactionRepo(),
+			Interval: config.Duration("REACTION_RECONCILE_INTERVAL", 6*time.Hour),
+		},
+		statsBackfill: &jobs.ArticleStatsBackfill{
+			Repo:      db.ArticleRepo(),
+			Renderer:  renderer,
+			BatchSize: 100,
+		},
+		analyticsRollup: &jobs.AnalyticsRollup{
+			Repo:     db.AnalyticsRepo(),
+			Interval: config.Duration("ANALYTICS_ROLLUP_INTERVAL", time.Hour),
+		},
+		trendingRanker: &jobs.TrendingRanker{
+			Repo:     db.TrendingRepo(),
+			Interval: config.Duration("TRENDING_UPDATE_INTERVAL", 5*time.Minute),
+		},
+		relatedArticles: &jobs.RelatedArticles{
+			Repo:     db.RelatedRepo(),
+			Limit:    config.Int("RELATED_ARTICLES_LIMIT", 20),
+			Interval: config.Duration("RELATED_ARTICLES_INTERVAL", 6*time.Hour),
+		},
 	}
 
 	return server
 }
+
+// StartBackgroundJobs launches the periodic maintenance jobs and the one-off
+// backfills. They stop when ctx is cancelled.
+func (s *FiberServer) StartBackgroundJobs(ctx context.Context) {
+	go s.accountPurger.Run(ctx)
+	go s.dataExporter.Run(ctx)
+	go s.auditRetention.Run(ctx)
+	go s.publishScheduler.Run(ctx)
+	go s.revisionPruner.Run(ctx)
+	go s.reactionReconciler.Run(ctx)
+	go s.statsBackfill.Run(ctx)
+	go s.analyticsRollup.Run(ctx)
+	go s.trendingRanker.Run(ctx)
+	go s.relatedArticles.Run(ctx)
+}
@@ -0,0 +1,158 @@
+// Package storage keeps uploaded files in an object store and serves them
+// from public URLs.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+)
+
+// Storage stores objects under slash separated keys.
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, body io.Reader) error
+	Delete(ctx context.Context, keys ...string) error
+	// URL returns the public address of an object.
+	URL(key string) string
+	// Key returns the key of the object served at url, and false when url
+	// is not the address of an object in the store.
+	Key(url string) (string, bool)
+}
+
+// Supabase stores objects in a public Supabase Storage bucket.
+type Supabase struct {
+	Endpoint string
+	Bucket   string
+	// ServiceRole is the key uploads are authorised with.
+	ServiceRole string
+	Client      *http.Client
+}
+
+// NewSupabase returns a store whose requests are bounded by the context
+// they are made with. The client sets no overall timeout, which would cut
+// off large uploads on slow connections; only connecting and waiting for
+// the response once the body is sent are timed.
+func NewSupabase(endpoint, bucket, serviceRole string) *Supabase {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.ResponseHeaderTimeout = 30 * time.Second
+
+	return &Supabase{
+		Endpoint:    strings.TrimRight(endpoint, "/"),
+		Bucket:      bucket,
+		ServiceRole: serviceRole,
+		Client:      &http.Client{Transport: transport},
+	}
+}
+
+// Put streams body to the bucket as it is read, wrapped in a multipart form
+// on the fly, so uploads are never held in memory as a whole. When reading
+// body fails, that error is returned rather than the aborted request's.
+func (s *Supabase) Put(ctx context.Context, key, contentType string, body io.Reader) error {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	// The form is written while the request is sent. Put waits for the
+	// writer to stop before returning, so body is never read once the
+	// caller is done with it.
+	written := make(chan error, 1)
+	go func() {
+		err := writeForm(writer, key, contentType, body)
+		pw.CloseWithError(err)
+		written <- err
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.objectURL(key), pr)
+	if err == nil {
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		err = s.do(req)
+	}
+	pr.CloseWithError(errAborted)
+	if werr := <-written; werr != nil && !errors.Is(werr, errAborted) {
+		return fmt.Errorf("failed to read upload: %w", werr)
+	}
+	return err
+}
+
+// errAborted stops the form writer when the request ends before the whole
+// body has been sent.
+var errAborted = errors.New("upload aborted")
+
+func writeForm(writer *multipart.Writer, key, contentType string, body io.Reader) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, path.Base(key)))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, body); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func (s *Supabase) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(map[string][]string{"prefixes": keys})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.Endpoint+"/storage/v1/object/"+s.Bucket, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return s.do(req)
+}
+
+func (s *Supabase) URL(key string) string {
+	return s.Endpoint + "/storage/v1/object/public/" + s.Bucket + "/" + key
+}
+
+func (s *Supabase) Key(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, s.URL(""))
+	return key, ok && key != ""
+}
+
+func (s *Supabase) objectURL(key string) string {
+	return s.Endpoint + "/storage/v1/object/" + s.Bucket + "/" + key
+}
+
+func (s *Supabase) do(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+s.ServiceRole)
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
+
+// Error is a request the object store turned down.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("storage responded %d: %s", e.StatusCode, e.Message)
+}
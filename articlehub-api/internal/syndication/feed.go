@@ -0,0 +1,277 @@
+// Package syndication renders article listings as RSS 2.0, Atom 1.0 and
+// JSON Feed 1.1 documents.
+package syndication
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+// ContentTypes maps every supported format to the media type it is served
+// with.
+var ContentTypes = map[string]string{
+	FormatRSS:  "application/rss+xml; charset=utf-8",
+	FormatAtom: "application/atom+xml; charset=utf-8",
+	FormatJSON: "application/feed+json; charset=utf-8",
+}
+
+// Feed is the format independent description of a feed.
+type Feed struct {
+	Title       string
+	Description string
+	// Link is the HTML page the feed mirrors, FeedURL the feed itself.
+	Link    string
+	FeedURL string
+	Updated time.Time
+	Items   []Item
+}
+
+type Item struct {
+	ID      string
+	Title   string
+	Link    string
+	Author  string
+	Summary string
+	// ContentHTML is empty for summary-only feeds.
+	ContentHTML string
+	Published   time.Time
+	Updated     time.Time
+	Tags        []string
+}
+
+// Render encodes feed in format, one of FormatRSS, FormatAtom or FormatJSON.
+func Render(feed *Feed, format string) ([]byte, error) {
+	switch format {
+	case FormatAtom:
+		return atom(feed)
+	case FormatJSON:
+		return jsonFeed(feed)
+	default:
+		return rss(feed)
+	}
+}
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	DCNS      string     `xml:"xmlns:dc,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+	Content     *cdata   `xml:"content:encoded,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+func rss(feed *Feed) ([]byte, error) {
+	doc := rssDocument{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		DCNS:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			AtomLink:      rssLink{Href: feed.FeedURL, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, item := range feed.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: false, Value: item.ID},
+			Creator:     item.Author,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Categories:  item.Tags,
+			Description: item.Summary,
+		}
+		if item.ContentHTML != "" {
+			entry.Content = &cdata{Value: item.ContentHTML}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, entry)
+	}
+	return marshalXML(doc)
+}
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Categories []atomCategory `xml:"category"`
+	Summary    atomText       `xml:"summary"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+func atom(feed *Feed) ([]byte, error) {
+	doc := atomDocument{
+		ID:       feed.FeedURL,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  feed.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+			{Href: feed.FeedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			ID:        "urn:uuid:" + item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Summary:   atomText{Type: "text", Value: item.Summary},
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		if item.ContentHTML != "" {
+			entry.Content = &atomText{Type: "html", Value: item.ContentHTML}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshalXML(doc)
+}
+
+func marshalXML(doc any) ([]byte, error) {
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+type jsonDocument struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url"`
+	FeedURL     string     `json:"feed_url"`
+	Description string     `json:"description,omitempty"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html,omitempty"`
+	ContentText   string       `json:"content_text,omitempty"`
+	Summary       string       `json:"summary,omitempty"`
+	DatePublished string       `json:"date_published"`
+	DateModified  string       `json:"date_modified"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+func jsonFeed(feed *Feed) ([]byte, error) {
+	doc := jsonDocument{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedURL,
+		Description: feed.Description,
+		Items:       []jsonItem{},
+	}
+	for _, item := range feed.Items {
+		entry := jsonItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			Summary:       item.Summary,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		// Every item needs either content_html or content_text.
+		if item.ContentHTML != "" {
+			entry.ContentHTML = item.ContentHTML
+		} else {
+			entry.ContentText = item.Summary
+		}
+		if item.Author != "" {
+			entry.Authors = []jsonAuthor{{Name: item.Author}}
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
@@ -0,0 +1,6 @@
+-- Soft deletion: deactivated accounts keep their row until the grace period
+-- elapses and the purge job deletes or anonymises them.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS purged_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    id           UUID PRIMARY KEY,
+    user_id      UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status       TEXT NOT NULL,
+    file_path    TEXT,
+    error        TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    expires_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_pending ON data_exports (created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports (expires_at);
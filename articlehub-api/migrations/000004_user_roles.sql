@@ -0,0 +1,10 @@
+-- Roles, suspension, forced password resets and session revocation.
+-- Promote the first administrator manually:
+--   UPDATE users SET role = 'admin' WHERE email = 'you@example.com';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tokens_valid_after TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON users (role);
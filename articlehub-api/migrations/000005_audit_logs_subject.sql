@@ -0,0 +1,4 @@
+-- The user an administrator was impersonating when the action was taken.
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS subject_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_subject_id ON audit_logs (subject_id, created_at) WHERE subject_id IS NOT NULL;
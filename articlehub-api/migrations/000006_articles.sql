@@ -0,0 +1,14 @@
+-- Articles keep both the Markdown source and the sanitised HTML rendered
+-- from it on write, so reads never have to render.
+CREATE TABLE IF NOT EXISTS articles (
+    id         UUID PRIMARY KEY,
+    author_id  UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title      TEXT NOT NULL,
+    body       TEXT NOT NULL,
+    body_html  TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_articles_author_id ON articles (author_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_created_at ON articles (created_at DESC);
@@ -0,0 +1,32 @@
+-- Publishing lifecycle: draft -> in_review -> scheduled -> published -> archived
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+-- Articles written before the workflow existed were public, so they stay
+-- published. Only new articles start out as drafts. The backfill runs once,
+-- when the column is added, as this file is applied again on every deploy.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'articles' AND column_name = 'status'
+    ) THEN
+        ALTER TABLE articles ADD COLUMN status TEXT NOT NULL DEFAULT 'published';
+        ALTER TABLE articles ALTER COLUMN status SET DEFAULT 'draft';
+        UPDATE articles SET published_at = created_at;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_articles_published ON articles (published_at DESC) WHERE status = 'published';
+CREATE INDEX IF NOT EXISTS idx_articles_scheduled ON articles (publish_at) WHERE status = 'scheduled';
+
+CREATE TABLE IF NOT EXISTS article_transitions (
+    id          UUID PRIMARY KEY,
+    article_id  UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status   TEXT NOT NULL,
+    actor_id    UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_transitions_article_id ON article_transitions (article_id, created_at);
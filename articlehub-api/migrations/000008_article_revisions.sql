@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id            UUID PRIMARY KEY,
+    article_id    UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    number        INTEGER NOT NULL,
+    editor_id     UUID REFERENCES users (id) ON DELETE SET NULL,
+    title         TEXT NOT NULL,
+    body          TEXT NOT NULL,
+    restored_from INTEGER,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (article_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_revisions_created_at ON article_revisions (created_at);
+
+-- Revisions are immutable, only the pruning job may delete them.
+CREATE OR REPLACE FUNCTION article_revisions_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'article_revisions is immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS article_revisions_no_update ON article_revisions;
+CREATE TRIGGER article_revisions_no_update BEFORE UPDATE ON article_revisions
+    FOR EACH ROW EXECUTE FUNCTION article_revisions_immutable();
+
+-- Snapshot existing articles as their first revision.
+INSERT INTO article_revisions (id, article_id, number, editor_id, title, body, created_at)
+SELECT gen_random_uuid(), id, 1, author_id, title, body, updated_at
+FROM articles a
+WHERE NOT EXISTS (SELECT 1 FROM article_revisions r WHERE r.article_id = a.id);
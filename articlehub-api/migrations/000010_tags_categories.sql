@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id         UUID PRIMARY KEY,
+    name       TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Alternative spellings resolved to a canonical tag, e.g. golang -> go.
+CREATE TABLE IF NOT EXISTS tag_aliases (
+    alias      TEXT PRIMARY KEY,
+    tag_id     UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    tag_id     UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id          UUID PRIMARY KEY,
+    parent_id   UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_name ON categories (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), lower(name));
+
+CREATE TABLE IF NOT EXISTS article_categories (
+    article_id  UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_categories_category_id ON article_categories (category_id);
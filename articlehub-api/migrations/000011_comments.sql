@@ -0,0 +1,19 @@
+-- Comments form a tree under an article. Deleted comments keep their row,
+-- with the body scrubbed, so replies stay attached to the thread.
+CREATE TABLE IF NOT EXISTS comments (
+    id         UUID PRIMARY KEY,
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    author_id  UUID REFERENCES users (id) ON DELETE SET NULL,
+    parent_id  UUID REFERENCES comments (id) ON DELETE CASCADE,
+    root_id    UUID NOT NULL,
+    depth      INTEGER NOT NULL DEFAULT 0,
+    body       TEXT NOT NULL,
+    body_html  TEXT NOT NULL,
+    edited_at  TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_article_roots ON comments (article_id, id) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_root_id ON comments (root_id, created_at);
@@ -0,0 +1,34 @@
+-- A reader holds at most one reaction per article or comment.
+CREATE TABLE IF NOT EXISTS article_reactions (
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reaction   TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (article_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id UUID NOT NULL REFERENCES comments (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reaction   TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+-- Denormalised counters, kept up to date in the same transaction as the
+-- reaction itself and periodically reconciled against the tables above.
+-- They live apart from articles and comments so that reacting neither locks
+-- nor bumps the version of the target row.
+CREATE TABLE IF NOT EXISTS article_reaction_counts (
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    reaction   TEXT NOT NULL,
+    count      INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, reaction)
+);
+
+CREATE TABLE IF NOT EXISTS comment_reaction_counts (
+    comment_id UUID NOT NULL REFERENCES comments (id) ON DELETE CASCADE,
+    reaction   TEXT NOT NULL,
+    count      INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (comment_id, reaction)
+);
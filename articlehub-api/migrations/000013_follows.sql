@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS user_follows (
+    follower_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_follows_followee ON user_follows (followee_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS tag_follows (
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    tag_id     UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, tag_id)
+);
+
+-- The feed reads the latest published articles of each followed author with
+-- an index-only walk, so its cost grows with the page size rather than with
+-- the total number of articles from followed authors.
+CREATE INDEX IF NOT EXISTS idx_articles_author_published ON articles (author_id, published_at DESC, id DESC)
+    WHERE status = 'published';
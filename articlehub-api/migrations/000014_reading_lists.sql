@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS bookmarks (
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    note       TEXT NOT NULL DEFAULT '',
+    added_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, article_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_user_added ON bookmarks (user_id, added_at DESC);
+
+-- Unlisted and public lists can be shared through their share_token; only
+-- public ones are shown on their owner's profile.
+CREATE TABLE IF NOT EXISTS reading_lists (
+    id          UUID PRIMARY KEY,
+    owner_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    visibility  TEXT NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'unlisted', 'public')),
+    share_token TEXT NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_lists_owner ON reading_lists (owner_id, created_at);
+
+CREATE TABLE IF NOT EXISTS reading_list_items (
+    list_id    UUID NOT NULL REFERENCES reading_lists (id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL,
+    note       TEXT NOT NULL DEFAULT '',
+    added_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, article_id)
+);
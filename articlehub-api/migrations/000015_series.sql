@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS series (
+    id          UUID PRIMARY KEY,
+    author_id   UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    version     INTEGER NOT NULL DEFAULT 1,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_author ON series (author_id, created_at DESC);
+
+-- An article belongs to at most one series. Positions are kept dense,
+-- starting at 1; the uniqueness check is deferred so that renumbering can
+-- swap positions within one statement.
+CREATE TABLE IF NOT EXISTS series_articles (
+    series_id  UUID NOT NULL REFERENCES series (id) ON DELETE CASCADE,
+    article_id UUID NOT NULL UNIQUE REFERENCES articles (id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL,
+    PRIMARY KEY (series_id, article_id),
+    CONSTRAINT series_articles_position_key UNIQUE (series_id, position) DEFERRABLE INITIALLY DEFERRED
+);
@@ -0,0 +1,36 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS slug TEXT;
+
+-- Existing rows get a best effort slug made unique with the tail of their ID.
+-- New slugs are generated by the application, which transliterates fully.
+UPDATE articles SET slug = COALESCE(NULLIF(trim(BOTH '-' FROM regexp_replace(
+        translate(lower(title), 'áàâãäéèêëíìîïóòôõöúùûüçñ', 'aaaaaeeeeiiiiooooouuuucn'),
+        '[^a-z0-9]+', '-', 'g')), ''), 'article') || '-' || right(id::text, 12)
+    WHERE slug IS NULL;
+UPDATE users SET slug = COALESCE(NULLIF(trim(BOTH '-' FROM regexp_replace(
+        translate(lower(name), 'áàâãäéèêëíìîïóòôõöúùûüçñ', 'aaaaaeeeeiiiiooooouuuucn'),
+        '[^a-z0-9]+', '-', 'g')), ''), 'user') || '-' || right(id::text, 12)
+    WHERE slug IS NULL;
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE users ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_slug ON articles (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_slug ON users (slug);
+
+-- Slugs an entity used to have, so that old links keep resolving. A slug is
+-- never handed out to another entity while it is in the history.
+CREATE TABLE IF NOT EXISTS article_slug_history (
+    slug       TEXT PRIMARY KEY,
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    retired_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_slug_history_article ON article_slug_history (article_id);
+
+CREATE TABLE IF NOT EXISTS user_slug_history (
+    slug       TEXT PRIMARY KEY,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    retired_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_slug_history_user ON user_slug_history (user_id);
@@ -0,0 +1,9 @@
+-- Computed by the application whenever an article is saved. Articles written
+-- before this migration have NULLs until the background backfill reaches
+-- them.
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS word_count   INTEGER,
+    ADD COLUMN IF NOT EXISTS reading_time INTEGER,
+    ADD COLUMN IF NOT EXISTS toc          JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_articles_without_stats ON articles (id) WHERE toc IS NULL;
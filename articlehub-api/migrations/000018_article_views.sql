@@ -0,0 +1,39 @@
+-- One random salt per UTC day. Visitors are identified by a hash of the
+-- salt, their IP address and user agent; once a day's salt is deleted the
+-- hashes can no longer be linked to anyone, not even by us.
+CREATE TABLE IF NOT EXISTS analytics_salts (
+    day  DATE PRIMARY KEY,
+    salt BYTEA NOT NULL
+);
+
+-- Raw visits, at most one per article, visitor and day. They only live until
+-- the rollup job has folded their day into the tables below.
+CREATE TABLE IF NOT EXISTS article_visits (
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    day        DATE NOT NULL,
+    visitor    BYTEA NOT NULL,
+    referrer   TEXT NOT NULL DEFAULT '',
+    read       BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (article_id, day, visitor)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_visits_day ON article_visits (day);
+
+CREATE TABLE IF NOT EXISTS article_daily_stats (
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    day        DATE NOT NULL,
+    views      INTEGER NOT NULL DEFAULT 0,
+    reads      INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, day)
+);
+
+-- Referrers are reduced to their host name; direct visits have none.
+CREATE TABLE IF NOT EXISTS article_daily_referrers (
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    day        DATE NOT NULL,
+    referrer   TEXT NOT NULL,
+    views      INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, day, referrer)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_reactions_created_at ON article_reactions (article_id, created_at);
@@ -0,0 +1,24 @@
+-- Trending scores are sums of activity decaying exponentially with age. They
+-- are stored relative to their window's epoch rather than to the time they
+-- were computed, so scores computed at different times compare directly and
+-- only articles with new activity need recomputing.
+CREATE TABLE IF NOT EXISTS trending_windows (
+    name        TEXT PRIMARY KEY,
+    epoch       TIMESTAMPTZ NOT NULL,
+    computed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS trending_scores (
+    window_name TEXT NOT NULL,
+    article_id  UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    score       DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (window_name, article_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trending_scores_rank ON trending_scores (window_name, score DESC);
+
+-- Activity is looked up by age when scores are computed.
+CREATE INDEX IF NOT EXISTS idx_article_reactions_created ON article_reactions (created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments (created_at);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_added_at ON bookmarks (added_at);
+CREATE INDEX IF NOT EXISTS idx_article_daily_stats_day ON article_daily_stats (day);
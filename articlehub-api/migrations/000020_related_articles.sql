@@ -0,0 +1,10 @@
+-- Nearest neighbours of every published article, recomputed from scratch by
+-- a background job.
+CREATE TABLE IF NOT EXISTS related_articles (
+    article_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    related_id UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    score      DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (article_id, related_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_related_articles_score ON related_articles (article_id, score DESC);
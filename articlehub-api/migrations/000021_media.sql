@@ -0,0 +1,20 @@
+-- Every user's image library. storage_keys lists the stored objects of the
+-- original and its variants, deleted together with the row.
+CREATE TABLE IF NOT EXISTS media (
+    id           UUID PRIMARY KEY,
+    owner_id     UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    url          TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    width        INTEGER NOT NULL,
+    height       INTEGER NOT NULL,
+    size         BIGINT NOT NULL,
+    alt          TEXT NOT NULL DEFAULT '',
+    variants     JSONB NOT NULL DEFAULT '[]',
+    storage_keys TEXT[] NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_owner ON media (owner_id, created_at DESC, id DESC);
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS cover_media_id UUID REFERENCES media (id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+-- When an export was claimed by a worker. Exports left processing for too
+-- long belong to a worker that died and are claimed again.
+ALTER TABLE data_exports ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_processing ON data_exports (claimed_at) WHERE status = 'processing';
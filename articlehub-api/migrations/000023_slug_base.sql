@@ -0,0 +1,5 @@
+-- The slug made from the current title or name, before any collision suffix
+-- was appended. A rename only moves the entity to a new slug when it changes.
+-- Rows without one get it on their next update.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug_base TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS slug_base TEXT;
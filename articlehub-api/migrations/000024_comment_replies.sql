@@ -0,0 +1,3 @@
+-- Direct replies of a comment, oldest first, for reply counts and the
+-- paginated replies of long threads.
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments (parent_id, created_at, id) WHERE parent_id IS NOT NULL;
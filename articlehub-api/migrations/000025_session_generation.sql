@@ -0,0 +1,16 @@
+-- Sessions are revoked by bumping a per-user generation that every token
+-- carries, rather than by comparing issue dates, which only have second
+-- precision. tokens_valid_after is kept as the time of the last revocation.
+-- Tokens issued before this migration carry no generation, which reads as 0,
+-- so users whose sessions were ever revoked start at 1 to keep those tokens
+-- revoked. The backfill runs once, when the column is added.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'session_generation'
+    ) THEN
+        ALTER TABLE users ADD COLUMN session_generation INTEGER NOT NULL DEFAULT 0;
+        UPDATE users SET session_generation = 1 WHERE tokens_valid_after IS NOT NULL;
+    END IF;
+END $$;
@@ -0,0 +1,22 @@
+-- When a published article is unpublished or deleted, including by the
+-- purge of its author's account, feeds that listed it change although none
+-- of the articles they still list did. The last time that happened to each
+-- author's articles is kept so feeds can tell when they last changed.
+CREATE TABLE IF NOT EXISTS published_removals (
+    author_id  UUID PRIMARY KEY,
+    removed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION record_published_removal() RETURNS trigger AS $$
+BEGIN
+    IF OLD.status = 'published' AND (TG_OP = 'DELETE' OR NEW.status <> 'published') THEN
+        INSERT INTO published_removals (author_id, removed_at) VALUES (OLD.author_id, NOW())
+        ON CONFLICT (author_id) DO UPDATE SET removed_at = EXCLUDED.removed_at;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS articles_published_removal ON articles;
+CREATE TRIGGER articles_published_removal AFTER UPDATE OF status OR DELETE ON articles
+    FOR EACH ROW EXECUTE FUNCTION record_published_removal();
@@ -0,0 +1,12 @@
+-- Stored objects of purged accounts that are still to be removed from
+-- storage: the files of their media library and their avatar. They are
+-- queued in the statement that purges the account, so none is lost if
+-- removing them fails, and cleared once storage has deleted them. Avatars
+-- are queued by URL, since the key they are stored under is only known to
+-- the store.
+CREATE TABLE IF NOT EXISTS purged_objects (
+    id          BIGSERIAL PRIMARY KEY,
+    storage_key TEXT NOT NULL DEFAULT '',
+    url         TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);